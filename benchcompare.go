@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// significanceLevel is the p-value below which a difference is considered real
+const significanceLevel = 0.05
+
+// benchmarkFile is the on-disk format written by -bench-save
+type benchmarkFile struct {
+	Benchmarks []*BenchmarkResult `json:"benchmarks"`
+}
+
+// SaveBenchmarks writes benchmark results so they can be used as a baseline later
+func SaveBenchmarks(path string, benchmarks []*BenchmarkResult) error {
+	data, err := json.MarshalIndent(benchmarkFile{Benchmarks: benchmarks}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding benchmarks: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing benchmarks: %w", err)
+	}
+	return nil
+}
+
+// LoadBenchmarkBaseline reads baseline benchmarks from a file written by
+// -bench-save, or from a recorded `go test -json` stream
+func LoadBenchmarkBaseline(path string) ([]*BenchmarkResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading benchmark baseline: %w", err)
+	}
+
+	var saved benchmarkFile
+	if err := json.Unmarshal(data, &saved); err == nil && saved.Benchmarks != nil {
+		return saved.Benchmarks, nil
+	}
+
+	processor, err := processRecordedRun(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading benchmark baseline: %w", err)
+	}
+
+	benchmarks := processor.GetBenchmarks()
+	if len(benchmarks) == 0 {
+		return nil, fmt.Errorf("benchmark baseline %s contains no benchmark results", path)
+	}
+	return benchmarks, nil
+}
+
+// benchmarkDelta compares one metric of a benchmark between baseline and current run
+type benchmarkDelta struct {
+	Package      string
+	Name         string
+	Unit         string
+	Old          []float64
+	New          []float64
+	OldMean      float64
+	NewMean      float64
+	DeltaPercent float64
+	PValue       float64 // Negative when there are not enough samples for a test
+	Significant  bool
+	Regression   bool
+	Improvement  bool
+}
+
+// compareBenchmarks pairs benchmarks present in both runs and computes the
+// change of every metric. A change in the worse direction beyond threshold
+// percent is a regression, unless the samples show it is not significant.
+func compareBenchmarks(baseline, current []*BenchmarkResult, threshold float64) []benchmarkDelta {
+	oldByKey := groupBenchmarkSamples(baseline)
+	newByKey := groupBenchmarkSamples(current)
+
+	var keys []string
+	for key := range newByKey {
+		if _, exists := oldByKey[key]; exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var deltas []benchmarkDelta
+	for _, key := range keys {
+		oldSamples, newSamples := oldByKey[key], newByKey[key]
+		pkgName, name, _ := strings.Cut(key, "\x00")
+
+		units := benchmarkUnits([]*benchmarkSummary{{Metrics: metricSet(newSamples)}})
+		for _, unit := range units {
+			oldValues := metricValues(oldSamples, unit)
+			newValues := metricValues(newSamples, unit)
+			if len(oldValues) == 0 || len(newValues) == 0 {
+				continue
+			}
+
+			delta := benchmarkDelta{
+				Package: pkgName,
+				Name:    name,
+				Unit:    unit,
+				Old:     oldValues,
+				New:     newValues,
+				OldMean: mean(oldValues),
+				NewMean: mean(newValues),
+				PValue:  -1,
+			}
+			if delta.OldMean != 0 {
+				delta.DeltaPercent = (delta.NewMean - delta.OldMean) / delta.OldMean * 100
+			}
+
+			// Without enough samples the threshold alone decides
+			delta.Significant = true
+			if len(oldValues) > 1 && len(newValues) > 1 {
+				delta.PValue = mannWhitneyPValue(oldValues, newValues)
+				delta.Significant = delta.PValue < significanceLevel
+			}
+
+			worse := delta.DeltaPercent
+			if higherIsBetter(unit) {
+				worse = -worse
+			}
+			delta.Regression = delta.Significant && worse > threshold
+			delta.Improvement = delta.Significant && -worse > threshold
+
+			deltas = append(deltas, delta)
+		}
+	}
+	return deltas
+}
+
+func groupBenchmarkSamples(benchmarks []*BenchmarkResult) map[string][]*BenchmarkResult {
+	grouped := make(map[string][]*BenchmarkResult)
+	for _, b := range benchmarks {
+		key := b.Package + "\x00" + b.FullName()
+		grouped[key] = append(grouped[key], b)
+	}
+	return grouped
+}
+
+func metricSet(samples []*BenchmarkResult) map[string]float64 {
+	set := make(map[string]float64)
+	for _, s := range samples {
+		for unit, value := range s.Metrics {
+			set[unit] = value
+		}
+	}
+	return set
+}
+
+func metricValues(samples []*BenchmarkResult, unit string) []float64 {
+	var values []float64
+	for _, s := range samples {
+		if value, exists := s.Metrics[unit]; exists {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// higherIsBetter reports whether larger values of the unit are improvements
+// (throughput-style units such as MB/s or ops/s)
+func higherIsBetter(unit string) bool {
+	return strings.HasSuffix(unit, "/s")
+}
+
+// countRegressions returns the number of deltas that are regressions
+func countRegressions(deltas []benchmarkDelta) int {
+	count := 0
+	for _, d := range deltas {
+		if d.Regression {
+			count++
+		}
+	}
+	return count
+}
+
+// mannWhitneyPValue returns the two-sided p-value of the Mann-Whitney U test.
+// The exact distribution is used for small samples without ties, and the
+// normal approximation with tie correction otherwise.
+func mannWhitneyPValue(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+
+	type sample struct {
+		value float64
+		fromX bool
+	}
+	all := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Assign average ranks to ties
+	rankSumX := 0.0
+	tieCorrection := 0.0
+	hasTies := false
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rankSumX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			hasTies = true
+			tieCorrection += t*t*t - t
+		}
+		i = j
+	}
+
+	u := rankSumX - float64(n1*(n1+1))/2
+
+	if !hasTies && n1+n2 <= 50 {
+		return exactMannWhitneyPValue(n1, n2, u)
+	}
+
+	n := float64(n1 + n2)
+	meanU := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	// Continuity correction
+	z := (math.Abs(u-meanU) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactMannWhitneyPValue computes the two-sided p-value from the exact
+// distribution of U for sample sizes n1 and n2
+func exactMannWhitneyPValue(n1, n2 int, u float64) float64 {
+	maxU := n1 * n2
+	// counts[i][j][k]: arrangements of i x's and j y's with U = k
+	counts := make([][][]float64, n1+1)
+	for i := range counts {
+		counts[i] = make([][]float64, n2+1)
+		for j := range counts[i] {
+			counts[i][j] = make([]float64, maxU+1)
+		}
+	}
+	for i := 0; i <= n1; i++ {
+		for j := 0; j <= n2; j++ {
+			if i == 0 || j == 0 {
+				counts[i][j][0] = 1
+				continue
+			}
+			for k := 0; k <= i*j; k++ {
+				// The largest value is either an x (beating all j y's) or a y
+				if k >= j {
+					counts[i][j][k] += counts[i-1][j][k-j]
+				}
+				counts[i][j][k] += counts[i][j-1][k]
+			}
+		}
+	}
+
+	total := 0.0
+	for _, c := range counts[n1][n2] {
+		total += c
+	}
+
+	k := int(math.Round(u))
+	lower, upper := 0.0, 0.0
+	for i, c := range counts[n1][n2] {
+		if i <= k {
+			lower += c
+		}
+		if i >= k {
+			upper += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// formatSignificance describes the statistical test result like benchstat does
+func formatSignificance(d benchmarkDelta) string {
+	samples := fmt.Sprintf("n=%d+%d", len(d.Old), len(d.New))
+	if d.PValue < 0 {
+		return samples
+	}
+	if !d.Significant {
+		return fmt.Sprintf("~ (p=%.3f %s)", d.PValue, samples)
+	}
+	return fmt.Sprintf("p=%.3f %s", d.PValue, samples)
+}
+
+// formatDeltaPercent formats a percentage change with an explicit sign
+func formatDeltaPercent(delta float64) string {
+	return fmt.Sprintf("%+.2f%%", delta)
+}
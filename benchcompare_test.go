@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func benchSamples(pkg, name, unit string, values ...float64) []*BenchmarkResult {
+	var samples []*BenchmarkResult
+	for _, v := range values {
+		samples = append(samples, &BenchmarkResult{
+			Package:    pkg,
+			Name:       name,
+			Procs:      8,
+			Iterations: 1000,
+			Metrics:    map[string]float64{unit: v},
+		})
+	}
+	return samples
+}
+
+func TestMannWhitneyPValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		x, y     []float64
+		expected float64
+	}{
+		{
+			name:     "completely separated samples",
+			x:        []float64{1, 2, 3, 4, 5},
+			y:        []float64{6, 7, 8, 9, 10},
+			expected: 2.0 / 252,
+		},
+		{
+			name:     "interleaved samples",
+			x:        []float64{1, 3, 5},
+			y:        []float64{2, 4, 6},
+			expected: 0.7,
+		},
+		{
+			name:     "identical samples",
+			x:        []float64{5, 5, 5},
+			y:        []float64{5, 5, 5},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := mannWhitneyPValue(tt.x, tt.y); math.Abs(got-tt.expected) > 1e-3 {
+				t.Errorf("mannWhitneyPValue() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	t.Parallel()
+	var baseline, current []*BenchmarkResult
+	baseline = append(baseline, benchSamples("example", "BenchmarkSlower", "ns/op", 100, 101, 99, 100, 102)...)
+	current = append(current, benchSamples("example", "BenchmarkSlower", "ns/op", 150, 151, 149, 152, 150)...)
+	baseline = append(baseline, benchSamples("example", "BenchmarkNoisy", "ns/op", 100, 200, 100, 200, 150)...)
+	current = append(current, benchSamples("example", "BenchmarkNoisy", "ns/op", 120, 210, 110, 190, 180)...)
+	baseline = append(baseline, benchSamples("example", "BenchmarkThroughput", "MB/s", 100)...)
+	current = append(current, benchSamples("example", "BenchmarkThroughput", "MB/s", 50)...)
+	current = append(current, benchSamples("example", "BenchmarkNew", "ns/op", 1)...)
+
+	deltas := compareBenchmarks(baseline, current, 10)
+
+	byName := make(map[string]benchmarkDelta)
+	for _, d := range deltas {
+		byName[d.Name] = d
+	}
+
+	if _, exists := byName["BenchmarkNew-8"]; exists {
+		t.Error("Benchmarks missing from the baseline should not be compared")
+	}
+
+	slower := byName["BenchmarkSlower-8"]
+	if !slower.Regression || !slower.Significant || math.Abs(slower.DeltaPercent-50) > 1 {
+		t.Errorf("Expected significant ~50%% regression, got %+v", slower)
+	}
+
+	if noisy := byName["BenchmarkNoisy-8"]; noisy.Regression || noisy.Significant {
+		t.Errorf("Noisy change should not be significant, got %+v", noisy)
+	}
+
+	throughput := byName["BenchmarkThroughput-8"]
+	if !throughput.Regression || throughput.PValue >= 0 {
+		t.Errorf("Lower throughput with a single sample should be a regression without p-value, got %+v", throughput)
+	}
+
+	if countRegressions(deltas) != 2 {
+		t.Errorf("Expected 2 regressions, got %d", countRegressions(deltas))
+	}
+}
+
+func TestSaveAndLoadBenchmarkBaseline(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "bench.json")
+	benchmarks := benchSamples("example", "BenchmarkJoin", "ns/op", 100, 110)
+
+	if err := SaveBenchmarks(path, benchmarks); err != nil {
+		t.Fatalf("SaveBenchmarks() error = %v", err)
+	}
+
+	loaded, err := LoadBenchmarkBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBenchmarkBaseline() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[1].Metrics["ns/op"] != 110 || loaded[0].FullName() != "BenchmarkJoin-8" {
+		t.Errorf("Unexpected baseline: %+v", loaded)
+	}
+}
+
+func TestLoadBenchmarkBaseline_FromStream(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "base.jsonl")
+	stream := `{"Action":"run","Package":"example","Test":"BenchmarkJoin"}
+{"Action":"output","Package":"example","Test":"BenchmarkJoin","Output":"BenchmarkJoin-8 \t 1000\t 188.0 ns/op\n"}
+{"Action":"pass","Package":"example","Elapsed":0.1}
+`
+	if err := os.WriteFile(path, []byte(stream), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBenchmarkBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBenchmarkBaseline() error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Metrics["ns/op"] != 188.0 {
+		t.Errorf("Unexpected baseline: %+v", loaded)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(empty, []byte(`{"Action":"pass","Package":"example"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBenchmarkBaseline(empty); err == nil {
+		t.Error("Expected error for a baseline without benchmarks")
+	}
+}
+
+func TestTerminalDisplay_BenchmarkRegressionFailsRun(t *testing.T) {
+	t.Parallel()
+	for _, ci := range []bool{false, true} {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, false)
+		baseline := benchSamples("example", "BenchmarkJoin", "ns/op", 100)
+		display.SetConfig(&Config{
+			CIMode:          ci,
+			BenchBaseline:   baseline,
+			BenchRegression: 10,
+			Policy:          &Policy{MaxSlowTests: -1, FailOnBenchRegression: true},
+		})
+
+		benchmarks := benchSamples("example", "BenchmarkJoin", "ns/op", 200)
+		deltas := compareBenchmarks(baseline, benchmarks, 10)
+		display.ShowBenchmarkResults(benchmarks, deltas)
+		exitCode := display.ShowFinalResults(map[string]*PackageState{}, map[string]*TestResult{}, deltas, time.Now())
+
+		output := buf.String()
+		if !strings.Contains(output, "Benchmark Comparison") || !strings.Contains(output, "+100.00%") {
+			t.Errorf("CI=%v: output should contain the comparison table, got:\n%s", ci, output)
+		}
+		if exitCode != 1 {
+			t.Errorf("CI=%v: expected exit code 1 on regression, got %d", ci, exitCode)
+		}
+		if !strings.Contains(output, "bench-regression") {
+			t.Errorf("CI=%v: output should list the regression as a policy violation", ci)
+		}
+	}
+}
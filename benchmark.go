@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Standard units reported by the testing package
+const (
+	unitNsPerOp     = "ns/op"
+	unitBytesPerOp  = "B/op"
+	unitAllocsPerOp = "allocs/op"
+)
+
+// BenchmarkResult holds a single benchmark result line
+// (e.g., "BenchmarkJoin-8  1000000  1234 ns/op  56 B/op  2 allocs/op")
+type BenchmarkResult struct {
+	Package    string             `json:"package"`
+	Name       string             `json:"name"`            // Benchmark name without the GOMAXPROCS suffix
+	Procs      int                `json:"procs,omitempty"` // GOMAXPROCS suffix (e.g., 8 for "-8"), 0 if absent
+	Iterations int64              `json:"iterations"`
+	Metrics    map[string]float64 `json:"metrics"`         // Value per unit (e.g., "ns/op": 1234)
+	Units      []string           `json:"units,omitempty"` // Units in the order they were reported
+}
+
+// FullName returns the benchmark name including the GOMAXPROCS suffix
+func (b *BenchmarkResult) FullName() string {
+	if b.Procs == 0 {
+		return b.Name
+	}
+	return fmt.Sprintf("%s-%d", b.Name, b.Procs)
+}
+
+// parseBenchmarkLine parses a benchmark result line.
+// It returns nil if the line is not a benchmark result.
+func parseBenchmarkLine(line string) *BenchmarkResult {
+	fields := strings.Fields(line)
+	// Name, iterations, and at least one value/unit pair
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return nil
+	}
+
+	iterations, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	name, procs := splitBenchmarkProcs(fields[0])
+	result := &BenchmarkResult{
+		Name:       name,
+		Procs:      procs,
+		Iterations: iterations,
+		Metrics:    make(map[string]float64),
+	}
+
+	for i := 2; i < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil
+		}
+		unit := fields[i+1]
+		if _, exists := result.Metrics[unit]; !exists {
+			result.Units = append(result.Units, unit)
+		}
+		result.Metrics[unit] = value
+	}
+
+	return result
+}
+
+// splitBenchmarkProcs splits "BenchmarkX-8" into "BenchmarkX" and 8
+func splitBenchmarkProcs(name string) (string, int) {
+	idx := strings.LastIndex(name, "-")
+	if idx == -1 {
+		return name, 0
+	}
+	procs, err := strconv.Atoi(name[idx+1:])
+	if err != nil || procs <= 0 {
+		return name, 0
+	}
+	return name[:idx], procs
+}
+
+// isBenchmarkName reports whether a test name belongs to a benchmark
+func isBenchmarkName(testName string) bool {
+	return strings.HasPrefix(testName, "Benchmark")
+}
+
+// benchmarkSummary aggregates all samples of one benchmark in a package
+type benchmarkSummary struct {
+	Package    string
+	Name       string // Full name including the GOMAXPROCS suffix
+	Samples    int
+	Iterations int64              // Mean iterations per sample
+	Metrics    map[string]float64 // Mean value per unit
+	Values     map[string][]float64
+}
+
+// summarizeBenchmarks groups benchmark samples by package and name.
+// The result maps package names to summaries sorted by benchmark name.
+func summarizeBenchmarks(benchmarks []*BenchmarkResult) map[string][]*benchmarkSummary {
+	byKey := make(map[string]*benchmarkSummary)
+	var order []*benchmarkSummary
+
+	for _, b := range benchmarks {
+		key := b.Package + "\x00" + b.FullName()
+		summary, exists := byKey[key]
+		if !exists {
+			summary = &benchmarkSummary{
+				Package: b.Package,
+				Name:    b.FullName(),
+				Metrics: make(map[string]float64),
+				Values:  make(map[string][]float64),
+			}
+			byKey[key] = summary
+			order = append(order, summary)
+		}
+		summary.Samples++
+		summary.Iterations += b.Iterations
+		for unit, value := range b.Metrics {
+			summary.Values[unit] = append(summary.Values[unit], value)
+		}
+	}
+
+	grouped := make(map[string][]*benchmarkSummary)
+	for _, summary := range order {
+		summary.Iterations /= int64(summary.Samples)
+		for unit, values := range summary.Values {
+			summary.Metrics[unit] = mean(values)
+		}
+		grouped[summary.Package] = append(grouped[summary.Package], summary)
+	}
+
+	for _, summaries := range grouped {
+		sort.Slice(summaries, func(i, j int) bool {
+			return summaries[i].Name < summaries[j].Name
+		})
+	}
+	return grouped
+}
+
+// benchmarkUnits returns the units reported by the summaries in display order:
+// ns/op, B/op and allocs/op first, then custom metrics alphabetically
+func benchmarkUnits(summaries []*benchmarkSummary) []string {
+	seen := make(map[string]bool)
+	for _, s := range summaries {
+		for unit := range s.Metrics {
+			seen[unit] = true
+		}
+	}
+
+	var units []string
+	for _, unit := range []string{unitNsPerOp, unitBytesPerOp, unitAllocsPerOp} {
+		if seen[unit] {
+			units = append(units, unit)
+			delete(seen, unit)
+		}
+	}
+
+	var custom []string
+	for unit := range seen {
+		custom = append(custom, unit)
+	}
+	sort.Strings(custom)
+	return append(units, custom...)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// formatBenchValue formats a metric value with a precision suited to its magnitude
+func formatBenchValue(value float64) string {
+	abs := math.Abs(value)
+	switch {
+	case value == 0:
+		return "0"
+	case abs >= 1000:
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	case abs >= 100:
+		return strconv.FormatFloat(value, 'f', 1, 64)
+	case abs >= 1:
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	default:
+		return strconv.FormatFloat(value, 'g', 3, 64)
+	}
+}
+
+// formatTable pads every cell so the columns line up.
+// The first column is left-aligned and the others are right-aligned.
+func formatTable(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := len([]rune(cell)); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if i == 0 {
+				cells[i] = cell + padding
+			} else {
+				cells[i] = padding + cell
+			}
+		}
+		lines[r] = strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+	return lines
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseBenchmarkLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		line       string
+		expectNil  bool
+		benchName  string
+		procs      int
+		iterations int64
+		metrics    map[string]float64
+	}{
+		{
+			name:       "standard units",
+			line:       "BenchmarkJoin-8   \t 1000000\t      1234 ns/op\t      56 B/op\t       2 allocs/op\n",
+			benchName:  "BenchmarkJoin",
+			procs:      8,
+			iterations: 1000000,
+			metrics:    map[string]float64{"ns/op": 1234, "B/op": 56, "allocs/op": 2},
+		},
+		{
+			name:       "custom metric and subbenchmark without procs",
+			line:       "BenchmarkSub/small \t    1000\t         1.168 ns/op\t         3.500 widgets/op\n",
+			benchName:  "BenchmarkSub/small",
+			iterations: 1000,
+			metrics:    map[string]float64{"ns/op": 1.168, "widgets/op": 3.5},
+		},
+		{
+			name:       "subbenchmark name containing a dash",
+			line:       "BenchmarkParse/a-b-4 \t 10\t 5 ns/op\n",
+			benchName:  "BenchmarkParse/a-b",
+			procs:      4,
+			iterations: 10,
+			metrics:    map[string]float64{"ns/op": 5},
+		},
+		{name: "name only", line: "BenchmarkJoin\n", expectNil: true},
+		{name: "not a benchmark", line: "TestJoin 10 5 ns/op\n", expectNil: true},
+		{name: "invalid iterations", line: "BenchmarkJoin abc 5 ns/op\n", expectNil: true},
+		{name: "invalid value", line: "BenchmarkJoin 10 abc ns/op\n", expectNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := parseBenchmarkLine(tt.line)
+			if tt.expectNil {
+				if result != nil {
+					t.Errorf("Expected nil, got %+v", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatal("Expected benchmark result, got nil")
+			}
+			if result.Name != tt.benchName || result.Procs != tt.procs || result.Iterations != tt.iterations {
+				t.Errorf("Got name=%q procs=%d iterations=%d", result.Name, result.Procs, result.Iterations)
+			}
+			for unit, want := range tt.metrics {
+				if got := result.Metrics[unit]; got != want {
+					t.Errorf("Metrics[%q] = %v, want %v", unit, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeBenchmarks(t *testing.T) {
+	t.Parallel()
+	benchmarks := []*BenchmarkResult{
+		{Package: "b", Name: "BenchmarkZ", Procs: 8, Iterations: 100, Metrics: map[string]float64{"ns/op": 10}},
+		{Package: "a", Name: "BenchmarkY", Iterations: 100, Metrics: map[string]float64{"ns/op": 30, "widgets/op": 1}},
+		{Package: "a", Name: "BenchmarkX", Iterations: 100, Metrics: map[string]float64{"ns/op": 20, "B/op": 4}},
+		{Package: "a", Name: "BenchmarkX", Iterations: 300, Metrics: map[string]float64{"ns/op": 40, "B/op": 4}},
+	}
+
+	grouped := summarizeBenchmarks(benchmarks)
+
+	if len(grouped["a"]) != 2 || len(grouped["b"]) != 1 {
+		t.Fatalf("Unexpected grouping: %+v", grouped)
+	}
+
+	x := grouped["a"][0]
+	if x.Name != "BenchmarkX" || x.Samples != 2 || x.Iterations != 200 || x.Metrics["ns/op"] != 30 {
+		t.Errorf("Unexpected summary for BenchmarkX: %+v", x)
+	}
+	if grouped["b"][0].Name != "BenchmarkZ-8" {
+		t.Errorf("Expected name with procs suffix, got %q", grouped["b"][0].Name)
+	}
+
+	units := benchmarkUnits(grouped["a"])
+	if strings.Join(units, ",") != "ns/op,B/op,widgets/op" {
+		t.Errorf("benchmarkUnits() = %v", units)
+	}
+}
+
+func TestFormatBenchValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{0, "0"},
+		{12345.6, "12346"},
+		{123.45, "123.5"},
+		{3.5, "3.50"},
+		{0.905, "0.905"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBenchValue(tt.value); got != tt.expected {
+			t.Errorf("formatBenchValue(%v) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestTerminalDisplay_ShowBenchmarkResults(t *testing.T) {
+	t.Parallel()
+	benchmarks := []*BenchmarkResult{
+		{Package: "example", Name: "BenchmarkJoin", Procs: 8, Iterations: 1000, Metrics: map[string]float64{"ns/op": 1234, "B/op": 56, "allocs/op": 2}},
+		{Package: "example", Name: "BenchmarkAdd", Procs: 8, Iterations: 5000, Metrics: map[string]float64{"ns/op": 1.5}},
+	}
+
+	for _, ci := range []bool{false, true} {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, false)
+		display.SetConfig(&Config{CIMode: ci})
+
+		display.ShowBenchmarkResults(benchmarks, nil)
+
+		output := buf.String()
+		for _, want := range []string{"Benchmarks", "ns/op", "B/op", "allocs/op", "BenchmarkJoin-8", "1234"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("CI=%v: output should contain %q, got:\n%s", ci, want, output)
+			}
+		}
+		if strings.Index(output, "BenchmarkAdd-8") > strings.Index(output, "BenchmarkJoin-8") {
+			t.Errorf("CI=%v: benchmarks should be sorted by name", ci)
+		}
+		if ci && strings.Contains(output, "\033[") {
+			t.Error("CI mode should not contain escape sequences")
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// changedFlag is the value of -changed, which may be given alone to compare
+// against the main branch or with a git ref (-changed=origin/release)
+type changedFlag struct {
+	enabled bool
+	ref     string
+}
+
+func (f *changedFlag) String() string {
+	if !f.enabled {
+		return "false"
+	}
+	if f.ref == "" {
+		return "true"
+	}
+	return f.ref
+}
+
+func (f *changedFlag) Set(value string) error {
+	switch value {
+	case "", "false":
+		f.enabled, f.ref = false, ""
+	case "true":
+		f.enabled, f.ref = true, ""
+	default:
+		f.enabled, f.ref = true, value
+	}
+	return nil
+}
+
+// IsBoolFlag lets -changed be given without a value
+func (f *changedFlag) IsBoolFlag() bool { return true }
+
+// mainBranches are the branches whose merge-base with HEAD is compared
+// against when -changed is given without a ref, in order of preference
+var mainBranches = []string{"main", "origin/main", "master", "origin/master"}
+
+// moduleFiles select every package when they change
+var moduleFiles = map[string]bool{"go.mod": true, "go.sum": true, "go.work": true, "go.work.sum": true}
+
+// changedSelection is a package selected by -changed and why
+type changedSelection struct {
+	Package string
+	Reason  string
+}
+
+func gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// changedBase resolves the commit to compare against and describes it:
+// the given ref, or the merge-base of HEAD with the main branch
+func changedBase(ref string) (string, string, error) {
+	if ref != "" {
+		base, err := gitOutput("rev-parse", "--verify", ref+"^{commit}")
+		if err != nil {
+			return "", "", fmt.Errorf("resolving ref %q: %w", ref, err)
+		}
+		return base, ref, nil
+	}
+
+	for _, branch := range mainBranches {
+		if base, err := gitOutput("merge-base", "HEAD", branch); err == nil {
+			return base, fmt.Sprintf("the merge-base with %s (%.7s)", branch, base), nil
+		}
+	}
+	return "", "", fmt.Errorf("no main branch to compare against (tried %s); give a git ref instead", strings.Join(mainBranches, ", "))
+}
+
+// gitRoot returns the root of the working tree. It is built from the working
+// directory rather than --show-toplevel, which resolves symlinks, so paths
+// match the directories from go list.
+func gitRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+	cdup, err := gitOutput("rev-parse", "--show-cdup")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(wd, cdup), nil
+}
+
+// gitChangedFiles returns the absolute paths of files changed since base,
+// including uncommitted changes and untracked files
+func gitChangedFiles(base string) ([]string, error) {
+	root, err := gitRoot()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := gitOutput("-C", root, "diff", "--name-only", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitOutput("-C", root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, name := range append(strings.Split(diff, "\n"), strings.Split(untracked, "\n")...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		files = append(files, filepath.Join(root, filepath.FromSlash(name)))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// packageOf returns the package of the main module containing a directory,
+// looking at parent directories for files such as testdata and embedded assets
+func (g *watchGraph) packageOf(dir string) (string, bool) {
+	for {
+		if pkg, exists := g.dirs[dir]; exists {
+			return pkg, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// selectChangedPackages maps changed files to their packages and selects the
+// targets whose tests depend on any of them. Files outside every package are
+// ignored, except module files, which select all targets.
+func selectChangedPackages(g *watchGraph, files []string) []changedSelection {
+	changedFilesOf := make(map[string][]string)
+	for _, file := range files {
+		if moduleFiles[filepath.Base(file)] {
+			selected := make([]changedSelection, 0, len(g.targets))
+			for _, target := range g.targets {
+				selected = append(selected, changedSelection{Package: target, Reason: "changed " + filepath.Base(file)})
+			}
+			return selected
+		}
+		if pkg, found := g.packageOf(filepath.Dir(file)); found {
+			changedFilesOf[pkg] = append(changedFilesOf[pkg], file)
+		}
+	}
+
+	changed := make(map[string]bool, len(changedFilesOf))
+	for pkg := range changedFilesOf {
+		changed[pkg] = true
+	}
+
+	var selected []changedSelection
+	for _, target := range g.targets {
+		deps := g.dependsOn(target, changed)
+		if len(deps) == 0 {
+			continue
+		}
+		reason := describeChanges(changedFilesOf[target])
+		if !changed[target] {
+			if len(deps) > 3 {
+				deps = append(deps[:3], fmt.Sprintf("%d more", len(deps)-3))
+			}
+			reason = "depends on " + strings.Join(deps, ", ")
+		}
+		selected = append(selected, changedSelection{Package: target, Reason: reason})
+	}
+	return selected
+}
+
+// runChanged implements -changed: it selects the packages affected by the
+// changes since a git ref and runs go test -json for them
+func runChanged(config *Config, stdout, stderr io.Writer) int {
+	patterns := config.Packages
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	base, description, err := changedBase(config.ChangedRef)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	files, err := gitChangedFiles(base)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	graph, err := loadWatchGraph(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	selected := selectChangedPackages(graph, files)
+	showChangedSelection(stdout, config, selected, len(graph.targets), description)
+	if len(selected) == 0 {
+		return 0
+	}
+
+	packages := make([]string, 0, len(selected))
+	for _, selection := range selected {
+		packages = append(packages, selection.Package)
+	}
+	return runGoTest(packages, NewEventProcessor(), config, false, stdout, stderr)
+}
+
+// showChangedSelection prints which packages -changed selected and why
+func showChangedSelection(w io.Writer, config *Config, selected []changedSelection, total int, description string) {
+	if len(selected) == 0 {
+		fmt.Fprintf(w, "No packages changed since %s\n", description)
+		return
+	}
+
+	width := 0
+	for _, selection := range selected {
+		width = max(width, len(selection.Package))
+	}
+	if config.CIMode {
+		fmt.Fprintf(w, "Testing %d of %d packages affected by changes since %s:\n", len(selected), total, description)
+		for _, selection := range selected {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, selection.Package, selection.Reason)
+		}
+	} else {
+		fmt.Fprintf(w, "%s🔍 Testing %d of %d packages affected by changes since %s:%s\n", colorBlue, len(selected), total, description, colorReset)
+		for _, selection := range selected {
+			fmt.Fprintf(w, "  %-*s  %s%s%s\n", width, selection.Package, colorGray, selection.Reason, colorReset)
+		}
+	}
+	fmt.Fprintln(w)
+}
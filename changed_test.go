@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChangedFlag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args        []string
+		enabled     bool
+		ref, string string
+	}{
+		{nil, false, "", "false"},
+		{[]string{"-changed"}, true, "", "true"},
+		{[]string{"-changed=origin/release"}, true, "origin/release", "origin/release"},
+		{[]string{"-changed=false"}, false, "", "false"},
+	}
+	for _, tt := range tests {
+		var changed changedFlag
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Var(&changed, "changed", "")
+		if err := flags.Parse(append(tt.args, "./pkg/...")); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if changed.enabled != tt.enabled || changed.ref != tt.ref || changed.String() != tt.string {
+			t.Errorf("%v: got %+v (%q)", tt.args, changed, changed.String())
+		}
+		if flags.Arg(0) != "./pkg/..." {
+			t.Errorf("%v: the package pattern should remain an argument, got %v", tt.args, flags.Args())
+		}
+	}
+}
+
+func TestSelectChangedPackages(t *testing.T) {
+	t.Parallel()
+	graph := parseWatchGraph([]string{"example/db", "example/api", "example/web"}, watchListing)
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected []changedSelection
+	}{
+		{
+			name:  "reverse dependencies",
+			files: []string{"/src/api/handler.go", "/src/api/handler_test.go"},
+			expected: []changedSelection{
+				{"example/api", "changed handler.go, handler_test.go"},
+				{"example/web", "depends on example/api"},
+			},
+		},
+		{
+			name:     "testdata belongs to its package",
+			files:    []string{"/src/web/testdata/golden.txt"},
+			expected: []changedSelection{{"example/web", "changed golden.txt"}},
+		},
+		{
+			name:     "files outside packages",
+			files:    []string{"/src/README.md"},
+			expected: nil,
+		},
+		{
+			name:  "module files",
+			files: []string{"/src/api/api.go", "/src/go.mod"},
+			expected: []changedSelection{
+				{"example/db", "changed go.mod"},
+				{"example/api", "changed go.mod"},
+				{"example/web", "changed go.mod"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := selectChangedPackages(graph, tt.files); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestGitChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("a/a.go", "package a\n")
+	write("b/b.go", "package b\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	git("checkout", "-q", "-b", "feature")
+	write("a/a.go", "package a\n\nvar X = 1\n")
+	git("commit", "-q", "-am", "change a")
+	write("b/b.go", "package b\n\nvar Y = 1\n")
+	write("c/c.go", "package c\n")
+
+	base, description, err := changedBase("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(description, "the merge-base with main") {
+		t.Errorf("unexpected description %q", description)
+	}
+	files, err := gitChangedFiles(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(root, "a/a.go"), filepath.Join(root, "b/b.go"), filepath.Join(root, "c/c.go")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected committed, modified and untracked files %v, got %v", expected, files)
+	}
+}
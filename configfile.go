@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envPrefix is the prefix of environment variables overriding settings
+// (e.g., GOTESTSHOW_THRESHOLD for -threshold)
+const envPrefix = "GOTESTSHOW_"
+
+// configFileNames are the project configuration files looked up from the
+// working directory upwards, in order of preference
+var configFileNames = []string{".gotestshow.yaml", ".gotestshow.yml", ".gotestshow.toml", ".gotestshow.json"}
+
+// unsettableFlags can only be given on the command line
+var unsettableFlags = map[string]bool{"help": true, "config": true}
+
+// pathSettings name file paths, which are relative to the configuration file
+// when set there
+var pathSettings = map[string]bool{
+	"thresholds":     true,
+	"rerun-script":   true,
+	"trace":          true,
+	"html":           true,
+	"markdown":       true,
+	"jsonfile":       true,
+	"tap":            true,
+	"quarantine":     true,
+	"coverprofile":   true,
+	"history":        true,
+	"bench-baseline": true,
+	"bench-save":     true,
+}
+
+// Settings records where the effective value of every flag came from
+type Settings struct {
+	File    string            // Configuration file that was loaded (empty if none)
+	Sources map[string]string // Flag name to "flag", "env GOTESTSHOW_X", "file" or "default"
+
+	flags *flag.FlagSet
+}
+
+// envName returns the environment variable overriding a flag
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// resolveSettings fills in flags that were not given on the command line,
+// first from the configuration file and then from the environment, so the
+// precedence is flags > environment > file > defaults. configPath selects
+// the file explicitly; otherwise it is discovered by walking up from dir.
+func resolveSettings(flags *flag.FlagSet, configPath, dir string, lookupEnv func(string) (string, bool)) (*Settings, error) {
+	settings := &Settings{Sources: make(map[string]string), flags: flags}
+	flags.VisitAll(func(f *flag.Flag) {
+		settings.Sources[f.Name] = "default"
+	})
+	flags.Visit(func(f *flag.Flag) {
+		settings.Sources[f.Name] = "flag"
+	})
+
+	if configPath == "" {
+		if path, ok := lookupEnv(envName("config")); ok {
+			configPath = path
+		}
+	}
+	if configPath == "" {
+		var err error
+		if configPath, err = findConfigFile(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if configPath != "" {
+		values, err := parseConfigFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		settings.File = configPath
+
+		for _, key := range sortedKeys(values) {
+			name, value := strings.ReplaceAll(key, "_", "-"), values[key]
+			if pathSettings[name] && value != "" && value != "-" && !filepath.IsAbs(value) {
+				value = filepath.Join(filepath.Dir(configPath), value)
+			}
+			if err := settings.apply(name, value, "file"); err != nil {
+				return nil, fmt.Errorf("%s: %w", configPath, err)
+			}
+		}
+	}
+
+	var envErr error
+	flags.VisitAll(func(f *flag.Flag) {
+		if envErr != nil || unsettableFlags[f.Name] {
+			return
+		}
+		name := envName(f.Name)
+		if value, ok := lookupEnv(name); ok {
+			if err := settings.apply(f.Name, value, "env "+name); err != nil {
+				envErr = fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	return settings, nil
+}
+
+// apply sets a flag from a lower-precedence source unless the command line set it
+func (s *Settings) apply(name, value, source string) error {
+	if s.flags.Lookup(name) == nil || unsettableFlags[name] {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if s.Sources[name] == "flag" {
+		return nil
+	}
+	if err := s.flags.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+	}
+	s.Sources[name] = source
+	return nil
+}
+
+// WriteTo prints the effective settings as YAML annotated with their sources,
+// so the output can be used as a configuration file
+func (s *Settings) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if s.File != "" {
+		fmt.Fprintf(&buf, "# Config file: %s\n", s.File)
+	} else {
+		fmt.Fprintln(&buf, "# Config file: none found")
+	}
+
+	type line struct{ setting, source string }
+	var lines []line
+	width := 0
+	s.flags.VisitAll(func(f *flag.Flag) {
+		if unsettableFlags[f.Name] {
+			return
+		}
+		setting := fmt.Sprintf("%s: %s", f.Name, yamlValue(f.Value.String()))
+		lines = append(lines, line{setting, s.Sources[f.Name]})
+		width = max(width, len(setting))
+	})
+	for _, l := range lines {
+		fmt.Fprintf(&buf, "%-*s  # %s\n", width, l.setting, l.source)
+	}
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// yamlValue quotes a value if it would not read back as the same plain scalar
+func yamlValue(value string) string {
+	if value == "" || strings.ContainsAny(value, "#:'\"") || strings.TrimSpace(value) != value {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// findConfigFile returns the first configuration file found in dir or its
+// parents, or an empty string if there is none
+func findConfigFile(dir string) (string, error) {
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err == nil && !info.IsDir() {
+				return path, nil
+			}
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("looking for config file: %w", err)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// parseConfigFile reads the flat key/value settings of a configuration file.
+// The format is chosen by the extension.
+func parseConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var values map[string]string
+	switch filepath.Ext(path) {
+	case ".json":
+		values, err = parseJSONSettings(data)
+	case ".toml":
+		values, err = parseFlatSettings(data, false)
+	default:
+		values, err = parseFlatSettings(data, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func parseJSONSettings(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%s: only strings, numbers and booleans are supported", key)
+		}
+	}
+	return values, nil
+}
+
+// parseFlatSettings parses the top-level "key: value" subset of YAML or the
+// "key = value" subset of TOML. Nested tables, lists and multi-line values
+// are rejected.
+func parseFlatSettings(data []byte, yaml bool) (map[string]string, error) {
+	separator := "="
+	if yaml {
+		separator = ":"
+	}
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		raw := scanner.Text()
+		line := strings.TrimSpace(stripComment(raw, yaml))
+		if line == "" || (yaml && line == "---") {
+			continue
+		}
+		nested := strings.HasPrefix(line, "[")
+		if yaml {
+			nested = raw[0] == ' ' || raw[0] == '\t' || strings.HasPrefix(line, "- ")
+		}
+		if nested {
+			return nil, fmt.Errorf("line %d: only top-level key/value settings are supported", lineNum)
+		}
+
+		key, value, found := strings.Cut(line, separator)
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key%svalue", lineNum, separator)
+		}
+		key = strings.TrimSpace(key)
+		value, err := unquoteSetting(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// stripComment removes a trailing "#" comment outside of quotes.
+// YAML only starts a comment at "#" preceded by whitespace.
+func stripComment(line string, yaml bool) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (!yaml || i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteSetting(value string) (string, error) {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid quoted string %s", value)
+			}
+			return unquoted, nil
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return value[1 : len(value)-1], nil
+		}
+	}
+	return value, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runConfigCommand implements `gotestshow config`, printing the effective
+// settings after merging flags, environment and configuration file
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	config, err := parseConfig(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			NewTerminalDisplay(stdout, true).ShowHelp()
+			return 0
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	if _, err := config.Settings.WriteTo(stdout); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFlagSet() (*flag.FlagSet, *bool, *string, *string) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	timing := flags.Bool("timing", false, "")
+	threshold := flags.String("threshold", "500ms", "")
+	quarantine := flags.String("quarantine", "", "")
+	flags.Bool("help", false, "")
+	return flags, timing, threshold, quarantine
+}
+
+func writeConfigFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func noEnv(string) (string, bool) { return "", false }
+
+func TestParseConfigFile(t *testing.T) {
+	t.Parallel()
+	expected := map[string]string{"timing": "true", "threshold": "1s", "fail-on-skip": "^TestDB#"}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{".gotestshow.yaml", "---\n# project settings\ntiming: true\nthreshold: 1s # slow\nfail-on-skip: \"^TestDB#\"\n"},
+		{".gotestshow.toml", "# project settings\ntiming = true\nthreshold = '1s'\nfail-on-skip = \"^TestDB#\" # skips\n"},
+		{".gotestshow.json", `{"timing": true, "threshold": "1s", "fail-on-skip": "^TestDB#"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := writeConfigFile(t, t.TempDir(), tt.name, tt.content)
+
+			values, err := parseConfigFile(path)
+			if err != nil {
+				t.Fatalf("parseConfigFile() error = %v", err)
+			}
+			if len(values) != len(expected) {
+				t.Errorf("parseConfigFile() = %v, want %v", values, expected)
+			}
+			for key, value := range expected {
+				if values[key] != value {
+					t.Errorf("%s = %q, want %q", key, values[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConfigFile_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"nested yaml", ".gotestshow.yaml", "thresholds:\n  unit: 1s\n"},
+		{"yaml list", ".gotestshow.yaml", "- timing\n"},
+		{"missing separator", ".gotestshow.yaml", "timing\n"},
+		{"toml table", ".gotestshow.toml", "[timing]\nenabled = true\n"},
+		{"nested json", ".gotestshow.json", `{"thresholds": {"unit": "1s"}}`},
+		{"invalid json", ".gotestshow.json", `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := writeConfigFile(t, t.TempDir(), tt.file, tt.content)
+			if _, err := parseConfigFile(path); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, err := findConfigFile(nested); err != nil || path != "" {
+		t.Errorf("findConfigFile() = (%q, %v), want no file", path, err)
+	}
+
+	expected := writeConfigFile(t, root, ".gotestshow.toml", "timing = true\n")
+	if path, err := findConfigFile(nested); err != nil || path != expected {
+		t.Errorf("findConfigFile() = (%q, %v), want %q", path, err, expected)
+	}
+}
+
+func TestResolveSettings_Precedence(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeConfigFile(t, dir, ".gotestshow.yaml", "timing: true\nthreshold: 1s\nquarantine: quarantine.json\n")
+
+	flags, timing, threshold, quarantine := newTestFlagSet()
+	if err := flags.Parse([]string{"-timing=false"}); err != nil {
+		t.Fatal(err)
+	}
+	env := func(name string) (string, bool) {
+		if name == "GOTESTSHOW_THRESHOLD" {
+			return "2s", true
+		}
+		return "", false
+	}
+
+	settings, err := resolveSettings(flags, "", dir, env)
+	if err != nil {
+		t.Fatalf("resolveSettings() error = %v", err)
+	}
+
+	if *timing {
+		t.Error("command line flag should take precedence over the config file")
+	}
+	if *threshold != "2s" {
+		t.Errorf("threshold = %q, environment should take precedence over the config file", *threshold)
+	}
+	if expected := filepath.Join(dir, "quarantine.json"); *quarantine != expected {
+		t.Errorf("quarantine = %q, want path relative to the config file %q", *quarantine, expected)
+	}
+
+	expectedSources := map[string]string{
+		"timing":     "flag",
+		"threshold":  "env GOTESTSHOW_THRESHOLD",
+		"quarantine": "file",
+		"help":       "default",
+	}
+	for name, source := range expectedSources {
+		if settings.Sources[name] != source {
+			t.Errorf("Sources[%q] = %q, want %q", name, settings.Sources[name], source)
+		}
+	}
+}
+
+func TestResolveSettings_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+	}{
+		{"unknown setting", "colour: true\n", nil},
+		{"command line only setting", "help: true\n", nil},
+		{"invalid file value", "timing: maybe\n", nil},
+		{"invalid env value", "", map[string]string{"GOTESTSHOW_TIMING": "maybe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := writeConfigFile(t, t.TempDir(), ".gotestshow.yaml", tt.content)
+			flags, _, _, _ := newTestFlagSet()
+			env := func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			if _, err := resolveSettings(flags, path, "", env); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestSettings_WriteTo(t *testing.T) {
+	t.Parallel()
+	flags, _, _, _ := newTestFlagSet()
+	if err := flags.Parse([]string{"-timing", "-quarantine=known broken.json"}); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := resolveSettings(flags, "", t.TempDir(), noEnv)
+	if err != nil {
+		t.Fatalf("resolveSettings() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := settings.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	for _, expected := range []string{
+		"# Config file: none found",
+		"timing: true",
+		"threshold: 500ms",
+		"quarantine: known broken.json",
+		"# flag",
+		"# default",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, but it didn't.\nGot: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "help:") {
+		t.Errorf("command line only settings should not be printed.\nGot: %s", output)
+	}
+
+	// The printed settings can be read back as a config file
+	path := writeConfigFile(t, t.TempDir(), ".gotestshow.yaml", output)
+	values, err := parseConfigFile(path)
+	if err != nil {
+		t.Fatalf("printed settings are not a valid config file: %v", err)
+	}
+	if values["quarantine"] != "known broken.json" || values["threshold"] != "500ms" {
+		t.Errorf("unexpected values read back: %v", values)
+	}
+}
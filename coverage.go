@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coverageRe matches the coverage line of go test -cover, e.g.
+// "coverage: 83.4% of statements" or "ok  	pkg	0.1s	coverage: 83.4% of statements in ./..."
+var coverageRe = regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`)
+
+// parseCoverageLine extracts the statement coverage percentage from an output line
+func parseCoverageLine(output string) (float64, bool) {
+	match := coverageRe.FindStringSubmatch(output)
+	if match == nil {
+		return 0, false
+	}
+	coverage, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return coverage, true
+}
+
+// CoverageRule sets the minimum coverage of matching packages
+type CoverageRule struct {
+	Package *regexp.Regexp // Matched against the full package import path (nil matches all)
+	Min     float64        // Minimum statement coverage in percent
+}
+
+// parseCoverageRules parses the value of -min-coverage: comma-separated
+// entries of "PERCENT" or "PATTERN=PERCENT", where PATTERN is a regexp
+// matched against the whole package import path
+func parseCoverageRules(value string) ([]CoverageRule, error) {
+	var rules []CoverageRule
+	for _, entry := range splitRules(value) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var rule CoverageRule
+		percent := entry
+		if i := strings.LastIndex(entry, "="); i >= 0 {
+			pattern, err := compileAnchored(entry[:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid -min-coverage pattern %q: %w", entry[:i], err)
+			}
+			rule.Package = pattern
+			percent = entry[i+1:]
+		}
+		minimum, err := strconv.ParseFloat(strings.TrimSuffix(percent, "%"), 64)
+		if err != nil || minimum < 0 || minimum > 100 {
+			return nil, fmt.Errorf("invalid -min-coverage percentage %q: must be between 0 and 100", percent)
+		}
+		rule.Min = minimum
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// splitRules splits comma-separated rules, leaving commas inside (), [] and {}
+// alone, as they belong to a pattern such as "pkg/v{1,3}"
+func splitRules(value string) []string {
+	var entries []string
+	depth, start := 0, 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++ // An escaped character never separates or nests
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth = max(depth-1, 0)
+		case ',':
+			if depth == 0 {
+				entries = append(entries, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(entries, value[start:])
+}
+
+// coverageMinimum returns the minimum coverage of the first rule matching the package
+func coverageMinimum(rules []CoverageRule, packageName string) (float64, bool) {
+	for _, rule := range rules {
+		if rule.Package == nil || rule.Package.MatchString(packageName) {
+			return rule.Min, true
+		}
+	}
+	return 0, false
+}
+
+// formatCoverage formats a coverage percentage like go test does
+func formatCoverage(coverage float64) string {
+	return fmt.Sprintf("%.1f%%", coverage)
+}
+
+// coverProfile is a parsed go test -coverprofile file
+type coverProfile struct {
+	Mode   string           // set, count or atomic
+	Blocks map[string]int64 // "file:start,end statements" -> count
+}
+
+// readCoverProfile parses a coverage profile
+func readCoverProfile(r io.Reader) (*coverProfile, error) {
+	profile := &coverProfile{Blocks: make(map[string]int64)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if mode, found := strings.CutPrefix(line, "mode: "); found {
+			if profile.Mode != "" && profile.Mode != mode {
+				return nil, fmt.Errorf("coverage profile mixes modes %s and %s", profile.Mode, mode)
+			}
+			profile.Mode = mode
+			continue
+		}
+
+		i := strings.LastIndex(line, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid coverage profile line %q", line)
+		}
+		count, err := strconv.ParseInt(line[i+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coverage profile line %q", line)
+		}
+		profile.add(line[:i], count)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// add records a block, combining it with an earlier record of the same block
+func (p *coverProfile) add(block string, count int64) {
+	if p.Mode == "set" {
+		p.Blocks[block] = max(p.Blocks[block], count)
+	} else {
+		p.Blocks[block] += count
+	}
+}
+
+// merge adds the blocks of another profile
+func (p *coverProfile) merge(other *coverProfile) error {
+	if p.Mode == "" {
+		p.Mode = other.Mode
+	} else if other.Mode != "" && other.Mode != p.Mode {
+		return fmt.Errorf("cannot merge coverage profiles of modes %s and %s", p.Mode, other.Mode)
+	}
+	for block, count := range other.Blocks {
+		p.add(block, count)
+	}
+	return nil
+}
+
+// dropPackages removes the blocks of files in the given packages
+func (p *coverProfile) dropPackages(packages map[string]bool) {
+	for block := range p.Blocks {
+		file, _, _ := strings.Cut(block, ":")
+		if packages[path.Dir(file)] {
+			delete(p.Blocks, block)
+		}
+	}
+}
+
+// statements returns the number of statements and how many of them ran
+func (p *coverProfile) statements() (int, int) {
+	total, covered := 0, 0
+	for block, count := range p.Blocks {
+		i := strings.LastIndex(block, " ")
+		n, err := strconv.Atoi(block[i+1:])
+		if err != nil {
+			continue
+		}
+		total += n
+		if count > 0 {
+			covered += n
+		}
+	}
+	return total, covered
+}
+
+// WriteTo writes the profile in the format go tool cover reads, sorted by block
+func (p *coverProfile) WriteTo(w io.Writer) (int64, error) {
+	blocks := make([]string, 0, len(p.Blocks))
+	for block := range p.Blocks {
+		blocks = append(blocks, block)
+	}
+	sort.Strings(blocks)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "mode: %s\n", p.Mode)
+	for _, block := range blocks {
+		fmt.Fprintf(&b, "%s %d\n", block, p.Blocks[block])
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// loadCoverProfile reads a coverage profile file
+func loadCoverProfile(path string) (*coverProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading coverage profile: %w", err)
+	}
+	defer file.Close()
+	profile, err := readCoverProfile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading coverage profile %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// writeCoverProfile writes a coverage profile file
+func writeCoverProfile(path string, profile *coverProfile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing coverage profile: %w", err)
+	}
+	if _, err := profile.WriteTo(file); err != nil {
+		file.Close()
+		return fmt.Errorf("writing coverage profile: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing coverage profile: %w", err)
+	}
+	return nil
+}
+
+// updateCoverProfile merges the profile of a go test run into path. The
+// blocks of the tested packages are replaced; with keep, blocks of other
+// packages already in the file are kept, so that reruns of some packages
+// in watch mode still produce a profile of all of them.
+func updateCoverProfile(path string, fresh *coverProfile, tested []string, keep bool) (*coverProfile, error) {
+	merged := &coverProfile{Blocks: make(map[string]int64)}
+	if keep {
+		if existing, err := loadCoverProfile(path); err == nil {
+			testedSet := make(map[string]bool, len(tested))
+			for _, pkg := range tested {
+				testedSet[pkg] = true
+			}
+			existing.dropPackages(testedSet)
+			merged = existing
+		}
+	}
+	if err := merged.merge(fresh); err != nil {
+		return nil, err
+	}
+	if err := writeCoverProfile(path, merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCoverageLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line     string
+		expected float64
+		ok       bool
+	}{
+		{"coverage: 83.4% of statements\n", 83.4, true},
+		{"ok  \texample\t0.1s\tcoverage: 100.0% of statements in ./...\n", 100, true},
+		{"\texample/tools\t\tcoverage: 0.0% of statements\n", 0, true},
+		{"coverage: [no statements]\n", 0, false},
+		{"PASS\n", 0, false},
+	}
+	for _, tt := range tests {
+		coverage, ok := parseCoverageLine(tt.line)
+		if coverage != tt.expected || ok != tt.ok {
+			t.Errorf("parseCoverageLine(%q) = %v, %v, expected %v, %v", tt.line, coverage, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestParseCoverageRules(t *testing.T) {
+	t.Parallel()
+	rules, err := parseCoverageRules("example/api/.*=90, example/.*=75%, 60")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pkg      string
+		expected float64
+	}{
+		{"example/api/v1", 90},
+		{"example/db", 75},
+		{"other", 60},
+		// Patterns match the whole import path
+		{"example/api", 75},
+	}
+	for _, tt := range tests {
+		if minimum, ok := coverageMinimum(rules, tt.pkg); !ok || minimum != tt.expected {
+			t.Errorf("coverageMinimum(%q) = %v, %v, expected %v", tt.pkg, minimum, ok, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"abc", "example=120", "([=50"} {
+		if _, err := parseCoverageRules(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestParseCoverageRules_CommaInPattern(t *testing.T) {
+	t.Parallel()
+	rules, err := parseCoverageRules(`example/v{1,3}=80,example/(a|b,c)=70,example/[,\]]=65,50`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 4 {
+		t.Fatalf("expected 4 rules, got %d", len(rules))
+	}
+
+	for pkg, expected := range map[string]float64{
+		"example/vv":  80,
+		"example/vvv": 80,
+		"example/b,c": 70,
+		"example/]":   65,
+		"example/v2":  50,
+	} {
+		if minimum, ok := coverageMinimum(rules, pkg); !ok || minimum != expected {
+			t.Errorf("coverageMinimum(%q) = %v, %v, expected %v", pkg, minimum, ok, expected)
+		}
+	}
+}
+
+func TestEventProcessor_Coverage(t *testing.T) {
+	t.Parallel()
+	processor := NewEventProcessor()
+	for _, event := range []TestEvent{
+		{Action: "start", Package: "example"},
+		{Action: "output", Package: "example", Output: "PASS\n"},
+		{Action: "output", Package: "example", Output: "coverage: 72.7% of statements\n"},
+		{Action: "output", Package: "example", Output: "ok  \texample\t0.1s\tcoverage: 72.7% of statements\n"},
+		{Action: "pass", Package: "example"},
+		{Action: "start", Package: "example/none"},
+		{Action: "output", Package: "example/none", Output: "?   \texample/none\t[no test files]\n"},
+		{Action: "skip", Package: "example/none"},
+	} {
+		processor.ProcessEvent(event)
+	}
+
+	packages := processor.GetPackages()
+	if pkg := packages["example"]; !pkg.HasCoverage || pkg.Coverage != 72.7 {
+		t.Errorf("expected 72.7%% coverage, got %v (reported: %v)", pkg.Coverage, pkg.HasCoverage)
+	}
+	if packages["example/none"].HasCoverage {
+		t.Error("example/none reported no coverage")
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_Coverage(t *testing.T) {
+	t.Parallel()
+	rules, _ := parseCoverageRules("example/db=80")
+	packages := map[string]*PackageState{
+		"example/api": {Name: "example/api", Total: 3, Passed: 3, Completed: true, Coverage: 91.2, HasCoverage: true},
+		"example/db":  {Name: "example/db", Total: 2, Passed: 1, Failed: 1, IndividualTestFailed: 1, Completed: true, Coverage: 45, HasCoverage: true},
+	}
+	results := map[string]*TestResult{
+		"example/api/TestGet":    {Package: "example/api", Test: "TestGet", Passed: true},
+		"example/db/TestQuery":   {Package: "example/db", Test: "TestQuery", Passed: true},
+		"example/db/TestConnect": {Package: "example/db", Test: "TestConnect", Failed: true},
+	}
+
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true, Policy: &Policy{MaxSlowTests: -1, MinCoverage: rules}})
+	display.ShowFinalResults(packages, results, nil, time.Now())
+
+	output := buf.String()
+	for _, expected := range []string{
+		"Tests: 2 | Passed: 1 | Failed: 1 | Skipped: 0 | Coverage: 45.0%",
+		"example/api      3 tests   91.2%\n",
+		"example/db       2 tests   45.0% (min 80.0%) BELOW MINIMUM",
+		"[min-coverage] example/db coverage 45.0% is below 80.0%",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, output)
+		}
+	}
+}
+
+func TestCoverProfile_Merge(t *testing.T) {
+	t.Parallel()
+	first, err := readCoverProfile(strings.NewReader("mode: count\nexample/a/a.go:3.10,5.2 2 1\nexample/b/b.go:1.1,2.2 1 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := readCoverProfile(strings.NewReader("mode: count\nexample/a/a.go:3.10,5.2 2 3\nexample/a/a.go:7.1,8.2 1 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := first.merge(second); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int64{
+		"example/a/a.go:3.10,5.2 2": 4,
+		"example/a/a.go:7.1,8.2 1":  0,
+		"example/b/b.go:1.1,2.2 1":  0,
+	}
+	if !reflect.DeepEqual(first.Blocks, expected) {
+		t.Errorf("expected counts to be added, got %v", first.Blocks)
+	}
+	if total, covered := first.statements(); total != 4 || covered != 2 {
+		t.Errorf("expected 2 of 4 statements covered, got %d of %d", covered, total)
+	}
+
+	set := &coverProfile{Mode: "set", Blocks: map[string]int64{"example/a/a.go:3.10,5.2 2": 1}}
+	if err := set.merge(second); err == nil {
+		t.Error("merging profiles of different modes should fail")
+	}
+}
+
+func TestUpdateCoverProfile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(path, []byte("mode: set\nexample/a/a.go:1.1,2.2 1 1\nexample/b/b.go:1.1,2.2 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rerunning example/b replaces its blocks and keeps those of example/a
+	fresh := &coverProfile{Mode: "set", Blocks: map[string]int64{"example/b/b.go:1.1,3.2 2": 0}}
+	if _, err := updateCoverProfile(path, fresh, []string{"example/b"}, true); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "mode: set\nexample/a/a.go:1.1,2.2 1 1\nexample/b/b.go:1.1,3.2 2 0\n"; string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if _, err := updateCoverProfile(path, fresh, []string{"example/b"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "example/a") {
+		t.Errorf("without keep, only the fresh profile should be written, got:\n%s", data)
+	}
+}
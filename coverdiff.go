@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lineRange is an inclusive range of line numbers
+type lineRange struct {
+	Start, End int
+}
+
+// coverBlock is a block of statements of a coverage profile
+type coverBlock struct {
+	Start, End int // Lines of the block
+	Count      int64
+}
+
+// fileLineCoverage is the coverage of the changed lines of one file
+type fileLineCoverage struct {
+	File      string // Path relative to the working tree root
+	Lines     int    // Changed lines that are statements
+	Covered   int
+	Uncovered []lineRange
+}
+
+// parseDiffHunks returns the lines added or modified in each file of a
+// git diff -U0, keyed by the path relative to the repository root
+func parseDiffHunks(diff string) map[string][]lineRange {
+	added := make(map[string][]lineRange)
+	file := ""
+	for _, line := range strings.Split(diff, "\n") {
+		if name, found := strings.CutPrefix(line, "+++ "); found {
+			file = ""
+			if name != "/dev/null" {
+				file = strings.TrimPrefix(name, "b/")
+			}
+			continue
+		}
+		if file == "" || !strings.HasPrefix(line, "@@ ") {
+			continue
+		}
+
+		// @@ -start[,count] +start[,count] @@
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+			continue
+		}
+		startText, countText, hasCount := strings.Cut(fields[2][1:], ",")
+		start, err := strconv.Atoi(startText)
+		if err != nil {
+			continue
+		}
+		count := 1
+		if hasCount {
+			if count, err = strconv.Atoi(countText); err != nil {
+				continue
+			}
+		}
+		if count > 0 {
+			added[file] = append(added[file], lineRange{start, start + count - 1})
+		}
+	}
+	return added
+}
+
+// coverBlocksByFile groups the blocks of a profile by file
+func coverBlocksByFile(profile *coverProfile) map[string][]coverBlock {
+	blocks := make(map[string][]coverBlock)
+	for block, count := range profile.Blocks {
+		// file:startLine.startCol,endLine.endCol statements
+		file, position, found := strings.Cut(block, ":")
+		if !found {
+			continue
+		}
+		position, _, _ = strings.Cut(position, " ")
+		startText, endText, _ := strings.Cut(position, ",")
+		startLine, _, _ := strings.Cut(startText, ".")
+		endLine, _, _ := strings.Cut(endText, ".")
+		start, startErr := strconv.Atoi(startLine)
+		end, endErr := strconv.Atoi(endLine)
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		blocks[file] = append(blocks[file], coverBlock{Start: start, End: end, Count: count})
+	}
+	return blocks
+}
+
+// changedLineCoverage checks every changed line against the blocks of its
+// file. Lines outside every block, such as comments and declarations, don't
+// count; a line is covered if any block containing it ran.
+func changedLineCoverage(file string, changed []lineRange, blocks []coverBlock) fileLineCoverage {
+	coverage := fileLineCoverage{File: file}
+	for _, r := range changed {
+		for line := r.Start; line <= r.End; line++ {
+			statement, covered := false, false
+			for _, block := range blocks {
+				if block.Start <= line && line <= block.End {
+					statement = true
+					covered = covered || block.Count > 0
+				}
+			}
+			if !statement {
+				continue
+			}
+			coverage.Lines++
+			if covered {
+				coverage.Covered++
+				continue
+			}
+			if n := len(coverage.Uncovered); n > 0 && coverage.Uncovered[n-1].End == line-1 {
+				coverage.Uncovered[n-1].End = line
+			} else {
+				coverage.Uncovered = append(coverage.Uncovered, lineRange{line, line})
+			}
+		}
+	}
+	return coverage
+}
+
+// formatLineRanges formats ranges as "3, 10-12"
+func formatLineRanges(ranges []lineRange) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Start == r.End {
+			parts = append(parts, strconv.Itoa(r.Start))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// gitAddedLines returns the lines added or modified since base in the .go
+// files of the working tree, keyed by path relative to the root, including
+// every line of untracked files
+func gitAddedLines(root, base string) (map[string][]lineRange, error) {
+	diff, err := gitOutput("-C", root, "diff", "-U0", "--no-color", "--no-ext-diff", base, "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	added := parseDiffHunks(diff)
+
+	untracked, err := gitOutput("-C", root, "ls-files", "--others", "--exclude-standard", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(untracked, "\n") {
+		if name == "" {
+			continue
+		}
+		lines, err := countLines(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		if lines > 0 {
+			added[name] = []lineRange{{1, lines}}
+		}
+	}
+	return added, nil
+}
+
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines, scanner.Err()
+}
+
+// moduleDirs returns the directories of the main modules by module path
+func moduleDirs() (map[string]string, error) {
+	listing, err := goList([]string{"-m", "-f", "{{.Path}}\t{{.Dir}}"})
+	if err != nil {
+		return nil, err
+	}
+	dirs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(listing), "\n") {
+		if path, dir, found := strings.Cut(line, "\t"); found && dir != "" {
+			dirs[path] = dir
+		}
+	}
+	return dirs, nil
+}
+
+// profileFileName returns the name a coverage profile uses for a file: its
+// module path followed by its path within the module
+func profileFileName(path string, modules map[string]string) (string, bool) {
+	best, name := "", ""
+	for modulePath, dir := range modules {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		// The innermost module wins
+		if len(dir) > len(best) {
+			best, name = dir, modulePath+"/"+filepath.ToSlash(rel)
+		}
+	}
+	return name, best != ""
+}
+
+// coverageDiff is the coverage of the lines changed since a base
+type coverageDiff struct {
+	Files      []fileLineCoverage
+	NotCovered []string // Changed files with no blocks in the profile, such as untested packages; their statements count as uncovered
+}
+
+func (d coverageDiff) totals() (int, int) {
+	lines, covered := 0, 0
+	for _, file := range d.Files {
+		lines += file.Lines
+		covered += file.Covered
+	}
+	return lines, covered
+}
+
+// computeCoverageDiff matches the changed lines with the profile. Test files
+// are skipped since they are not instrumented. Files missing from the profile
+// belong to packages no test ran, so their statements count as uncovered.
+func computeCoverageDiff(added map[string][]lineRange, root string, profile *coverProfile, modules map[string]string) coverageDiff {
+	blocks := coverBlocksByFile(profile)
+
+	files := make([]string, 0, len(added))
+	for file := range added {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var diff coverageDiff
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		name, found := profileFileName(filepath.Join(root, filepath.FromSlash(file)), modules)
+		if !found {
+			continue
+		}
+		fileBlocks, exists := blocks[name]
+		if !exists {
+			diff.NotCovered = append(diff.NotCovered, file)
+			fileBlocks = statementBlocks(filepath.Join(root, filepath.FromSlash(file)))
+		}
+		if coverage := changedLineCoverage(file, added[file], fileBlocks); coverage.Lines > 0 {
+			diff.Files = append(diff.Files, coverage)
+		}
+	}
+	return diff
+}
+
+// statementBlocks approximates the blocks go test -cover would instrument in
+// a file missing from the profile: the statements of every function body,
+// none of which ran. A file that can't be parsed is one block, so all of its
+// changed lines count as uncovered statements.
+func statementBlocks(path string) []coverBlock {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return []coverBlock{{Start: 1, End: math.MaxInt}}
+	}
+
+	var blocks []coverBlock
+	ast.Inspect(file, func(node ast.Node) bool {
+		var body *ast.BlockStmt
+		switch n := node.(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body == nil {
+			return true
+		}
+		// Function literals within the statements are part of their lines
+		for _, stmt := range body.List {
+			blocks = append(blocks, coverBlock{Start: fset.Position(stmt.Pos()).Line, End: fset.Position(stmt.End()).Line})
+		}
+		return false
+	})
+	return blocks
+}
+
+// renderCoverageDiff prints the per-file table of changed lines
+func renderCoverageDiff(w io.Writer, diff coverageDiff, description string, ciMode bool) {
+	if ciMode {
+		fmt.Fprintf(w, "Coverage of lines changed since %s\n", description)
+	} else {
+		fmt.Fprintf(w, "🧪 Coverage of lines changed since %s%s%s\n", colorBlue, description, colorReset)
+	}
+
+	if len(diff.Files) == 0 {
+		fmt.Fprintln(w, "\nNo changed statements")
+	} else {
+		rows := [][]string{{"File", "Lines", "Covered", "Coverage"}}
+		for _, file := range diff.Files {
+			rows = append(rows, []string{file.File, strconv.Itoa(file.Lines), strconv.Itoa(file.Covered), formatCoverage(lineCoverage(file.Lines, file.Covered))})
+		}
+		lines, covered := diff.totals()
+		rows = append(rows, []string{"Total", strconv.Itoa(lines), strconv.Itoa(covered), formatCoverage(lineCoverage(lines, covered))})
+
+		table := formatTable(rows)
+		fmt.Fprintln(w)
+		for i, line := range table {
+			switch {
+			case i == 0:
+				line += "  Uncovered lines"
+				if !ciMode {
+					line = colorGray + line + colorReset
+				}
+			case i <= len(diff.Files):
+				file := diff.Files[i-1]
+				if uncovered := formatLineRanges(file.Uncovered); uncovered != "" {
+					if ciMode {
+						line += "  " + uncovered
+					} else {
+						line += "  " + colorRed + uncovered + colorReset
+					}
+				}
+			}
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+
+	if len(diff.NotCovered) > 0 {
+		fmt.Fprintln(w, "\nChanged files not in the coverage profile (their statements count as uncovered):")
+		for _, file := range diff.NotCovered {
+			fmt.Fprintf(w, "  %s\n", file)
+		}
+	}
+}
+
+func lineCoverage(lines, covered int) float64 {
+	if lines == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(lines)
+}
+
+// runCoverDiffCommand implements `gotestshow coverdiff`
+func runCoverDiffCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("coverdiff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ci := flags.Bool("ci", false, "Disable colors and decorations")
+	base := flags.String("base", "", "Git ref the change is compared against (default: the merge-base with main)")
+	minimum := flags.Float64("min", 0, "Fail if less than this percentage of the changed statements is covered (0 to disable)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gotestshow coverdiff [flags] cover.out...")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Reports which lines added or modified since a git ref are not covered")
+		fmt.Fprintln(stderr, "by any test, according to the given go test -coverprofile files.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	profile := &coverProfile{Blocks: make(map[string]int64)}
+	for _, path := range flags.Args() {
+		other, err := loadCoverProfile(path)
+		if err == nil {
+			err = profile.merge(other)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	baseCommit, description, err := changedBase(*base)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	root, err := gitRoot()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	added, err := gitAddedLines(root, baseCommit)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	modules, err := moduleDirs()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	diff := computeCoverageDiff(added, root, profile, modules)
+	renderCoverageDiff(stdout, diff, description, *ci)
+
+	lines, covered := diff.totals()
+	if *minimum > 0 && lineCoverage(lines, covered) < *minimum {
+		if *ci {
+			fmt.Fprintf(stdout, "\nNew code coverage %s is below %s\n", formatCoverage(lineCoverage(lines, covered)), formatCoverage(*minimum))
+		} else {
+			fmt.Fprintf(stdout, "\n%s❌ New code coverage %s is below %s%s\n", colorRed, formatCoverage(lineCoverage(lines, covered)), formatCoverage(*minimum), colorReset)
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const coverDiffFixture = `diff --git a/api/handler.go b/api/handler.go
+index 1111111..2222222 100644
+--- a/api/handler.go
++++ b/api/handler.go
+@@ -3,0 +4,3 @@ import "fmt"
++// Handle handles
++func Handle(x int) int {
++	return x
+@@ -10 +13 @@ func old() {
+-	return 1
++	return 2
+@@ -20,2 +23,0 @@ func removed() {
+-	a()
+-	b()
+diff --git a/api/gone.go b/api/gone.go
+deleted file mode 100644
+--- a/api/gone.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package api
+`
+
+func TestParseDiffHunks(t *testing.T) {
+	t.Parallel()
+	expected := map[string][]lineRange{
+		"api/handler.go": {{4, 6}, {13, 13}},
+	}
+	if got := parseDiffHunks(coverDiffFixture); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestChangedLineCoverage(t *testing.T) {
+	t.Parallel()
+	blocks := []coverBlock{
+		{Start: 5, End: 7, Count: 1},
+		{Start: 7, End: 9, Count: 0},
+		{Start: 12, End: 14, Count: 0},
+	}
+	changed := []lineRange{{1, 9}, {13, 20}}
+
+	got := changedLineCoverage("api/handler.go", changed, blocks)
+	expected := fileLineCoverage{
+		File:    "api/handler.go",
+		Lines:   7,
+		Covered: 3,
+		// Line 7 is shared with a covered block
+		Uncovered: []lineRange{{8, 9}, {13, 14}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if ranges := formatLineRanges(got.Uncovered); ranges != "8-9, 13-14" {
+		t.Errorf("unexpected ranges %q", ranges)
+	}
+}
+
+func TestProfileFileName(t *testing.T) {
+	t.Parallel()
+	modules := map[string]string{
+		"example.com/app":       "/src/app",
+		"example.com/app/tools": "/src/app/tools",
+	}
+	tests := []struct {
+		path     string
+		expected string
+		found    bool
+	}{
+		{"/src/app/api/handler.go", "example.com/app/api/handler.go", true},
+		{"/src/app/tools/gen/main.go", "example.com/app/tools/gen/main.go", true},
+		{"/src/other/main.go", "", false},
+	}
+	for _, tt := range tests {
+		name, found := profileFileName(tt.path, modules)
+		if name != tt.expected || found != tt.found {
+			t.Errorf("profileFileName(%q) = %q, %v, expected %q, %v", tt.path, name, found, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestComputeCoverageDiff(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "cmd", "tool", "main.go"), `package main
+
+import "fmt"
+
+// main prints a greeting
+func main() {
+	name := "tool"
+	fmt.Println("hello", name)
+}
+`)
+	profile, err := readCoverProfile(strings.NewReader(`mode: set
+example.com/app/api/handler.go:5.24,7.2 1 1
+example.com/app/api/handler.go:13.2,13.10 1 0
+example.com/app/db/db.go:3.10,4.2 1 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := map[string][]lineRange{
+		"api/handler.go":      {{4, 6}, {13, 13}},
+		"api/handler_test.go": {{1, 30}},
+		"db/db.go":            {{10, 12}},
+		"cmd/tool/main.go":    {{1, 9}},
+	}
+
+	diff := computeCoverageDiff(added, root, profile, map[string]string{"example.com/app": root})
+
+	expected := []fileLineCoverage{
+		{File: "api/handler.go", Lines: 3, Covered: 2, Uncovered: []lineRange{{13, 13}}},
+		{File: "cmd/tool/main.go", Lines: 2, Covered: 0, Uncovered: []lineRange{{7, 8}}},
+	}
+	if !reflect.DeepEqual(diff.Files, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diff.Files)
+	}
+	if !reflect.DeepEqual(diff.NotCovered, []string{"cmd/tool/main.go"}) {
+		t.Errorf("expected the untested file to be listed, got %v", diff.NotCovered)
+	}
+
+	var buf bytes.Buffer
+	renderCoverageDiff(&buf, diff, "main", true)
+	output := buf.String()
+	for _, expected := range []string{
+		"Coverage of lines changed since main",
+		"  File              Lines  Covered  Coverage  Uncovered lines\n",
+		"  api/handler.go        3        2     66.7%  13\n",
+		"  cmd/tool/main.go      2        0      0.0%  7-8\n",
+		"  Total                 5        2     40.0%\n",
+		"Changed files not in the coverage profile (their statements count as uncovered):\n  cmd/tool/main.go",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, output)
+		}
+	}
+}
+
+func TestComputeCoverageDiff_OnlyUntestedFile(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "newpkg", "newpkg.go"), `package newpkg
+
+func Double(n int) int {
+	return n * 2
+}
+
+var square = func(n int) int {
+	return n * n
+}
+`)
+	profile, err := readCoverProfile(strings.NewReader("mode: set\nexample.com/app/db/db.go:3.10,4.2 1 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := computeCoverageDiff(map[string][]lineRange{"newpkg/newpkg.go": {{1, 9}}}, root, profile, map[string]string{"example.com/app": root})
+
+	lines, covered := diff.totals()
+	if lines != 2 || covered != 0 {
+		t.Errorf("expected the 2 statements of the untested package to be uncovered, got %d of %d covered", covered, lines)
+	}
+	if coverage := lineCoverage(lines, covered); coverage >= 80 {
+		t.Errorf("an untested package must not pass a minimum, got %s", formatCoverage(coverage))
+	}
+
+	// A file that can't be parsed counts every changed line
+	diff = computeCoverageDiff(map[string][]lineRange{"missing/missing.go": {{3, 5}}}, root, profile, map[string]string{"example.com/app": root})
+	if lines, covered := diff.totals(); lines != 3 || covered != 0 {
+		t.Errorf("expected the 3 changed lines to be uncovered, got %d of %d covered", covered, lines)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
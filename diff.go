@@ -0,0 +1,301 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// testChange describes how a test differs between two runs
+type testChange struct {
+	Package string
+	Test    string
+	Base    *TestResult // nil if the test did not run in the base run
+	Head    *TestResult // nil if the test did not run in the head run
+}
+
+// runDiff is the categorized difference between a base run and a head run
+type runDiff struct {
+	NewlyFailing []testChange // Passed or skipped in base, failed in head
+	NewlyPassing []testChange // Failed in base, passed in head
+	Changed      []testChange // Neither newly failing nor passing, but with another status, e.g. failed in base and skipped in head
+	Appeared     []testChange // Only in head
+	Disappeared  []testChange // Only in base
+	Slower       []testChange // Significantly slower in head
+}
+
+// diffOptions controls when a test counts as significantly slower
+type diffOptions struct {
+	Threshold time.Duration // Minimum absolute slowdown
+	Ratio     float64       // Minimum head/base elapsed ratio
+}
+
+// loadRecordedRun feeds a recorded `go test -json` stream through the event processor
+func loadRecordedRun(path string) (EventProcessor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening recorded run: %w", err)
+	}
+	defer file.Close()
+
+	processor, err := processRecordedRun(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading recorded run %s: %w", path, err)
+	}
+	return processor, nil
+}
+
+// diffableResults returns the results of a run, dropping package failures
+// that are only caused by failing tests
+func diffableResults(processor EventProcessor) map[string]*TestResult {
+	results := processor.GetResults()
+	packages := processor.GetPackages()
+	for key, result := range results {
+		if result.Test != "[PACKAGE]" {
+			continue
+		}
+		if pkg, exists := packages[result.Package]; exists && !shouldDisplayPackageFailure(pkg) {
+			delete(results, key)
+		}
+	}
+	return results
+}
+
+// diffRuns compares the results of two runs
+func diffRuns(base, head map[string]*TestResult, opts diffOptions) runDiff {
+	var diff runDiff
+
+	for key, headResult := range head {
+		if headResult.HasSubtest {
+			continue
+		}
+		baseResult, exists := base[key]
+		if exists && baseResult.HasSubtest {
+			exists = false
+		}
+		change := testChange{Package: headResult.Package, Test: headResult.Test, Head: headResult}
+
+		if !exists {
+			// Build and package failures only exist when they fail
+			if isSyntheticResult(headResult) {
+				diff.NewlyFailing = append(diff.NewlyFailing, change)
+			} else {
+				diff.Appeared = append(diff.Appeared, change)
+			}
+			continue
+		}
+		change.Base = baseResult
+
+		switch {
+		case headResult.Failed && !baseResult.Failed:
+			diff.NewlyFailing = append(diff.NewlyFailing, change)
+		case !headResult.Failed && baseResult.Failed && headResult.Passed:
+			diff.NewlyPassing = append(diff.NewlyPassing, change)
+		case describeStatus(headResult) != describeStatus(baseResult):
+			diff.Changed = append(diff.Changed, change)
+		case !isSyntheticResult(headResult) && isSignificantlySlower(baseResult.Elapsed, headResult.Elapsed, opts):
+			diff.Slower = append(diff.Slower, change)
+		}
+	}
+
+	for key, baseResult := range base {
+		if baseResult.HasSubtest {
+			continue
+		}
+		if headResult, exists := head[key]; exists && !headResult.HasSubtest {
+			continue
+		}
+		change := testChange{Package: baseResult.Package, Test: baseResult.Test, Base: baseResult}
+		if isSyntheticResult(baseResult) {
+			// A build or package failure that went away
+			if baseResult.Failed {
+				diff.NewlyPassing = append(diff.NewlyPassing, change)
+			}
+			continue
+		}
+		diff.Disappeared = append(diff.Disappeared, change)
+	}
+
+	for _, changes := range [][]testChange{diff.NewlyFailing, diff.NewlyPassing, diff.Changed, diff.Appeared, diff.Disappeared} {
+		sortTestChanges(changes)
+	}
+	// Largest slowdown first
+	sort.Slice(diff.Slower, func(i, j int) bool {
+		a, b := diff.Slower[i], diff.Slower[j]
+		return a.Head.Elapsed-a.Base.Elapsed > b.Head.Elapsed-b.Base.Elapsed
+	})
+
+	return diff
+}
+
+func isSignificantlySlower(baseElapsed, headElapsed float64, opts diffOptions) bool {
+	slowdown := time.Duration((headElapsed - baseElapsed) * float64(time.Second))
+	if slowdown <= opts.Threshold {
+		return false
+	}
+	if baseElapsed <= 0 {
+		return true
+	}
+	return headElapsed/baseElapsed >= opts.Ratio
+}
+
+func sortTestChanges(changes []testChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Test < changes[j].Test
+	})
+}
+
+// hasNewFailures reports whether head has failures that base did not have
+func (d runDiff) hasNewFailures() bool {
+	if len(d.NewlyFailing) > 0 {
+		return true
+	}
+	for _, change := range d.Appeared {
+		if change.Head.Failed {
+			return true
+		}
+	}
+	return false
+}
+
+// renderRunDiff writes the categorized diff report
+func renderRunDiff(w io.Writer, diff runDiff, ciMode bool) {
+	sections := []struct {
+		icon, title, color string
+		changes            []testChange
+		describe           func(testChange) string
+	}{
+		{"✗", "Newly failing", colorRed, diff.NewlyFailing, describeHeadChange},
+		{"✓", "Newly passing", colorGreen, diff.NewlyPassing, describeHeadChange},
+		{"~", "Changed status", colorYellow, diff.Changed, describeStatusChange},
+		{"+", "Appeared", colorBlue, diff.Appeared, describeHeadChange},
+		{"-", "Disappeared", colorGray, diff.Disappeared, describeBaseChange},
+		{"🐢", "Got slower", colorYellow, diff.Slower, describeSlowdown},
+	}
+
+	empty := true
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		empty = false
+
+		if ciMode {
+			fmt.Fprintf(w, "\n%s (%d)\n", section.title, len(section.changes))
+		} else {
+			fmt.Fprintf(w, "\n%s%s %s (%d)%s\n", section.color, section.icon, section.title, len(section.changes), colorReset)
+		}
+		for _, change := range section.changes {
+			fmt.Fprintf(w, "    %s %s%s\n", change.Test, getShortPackageName(change.Package), section.describe(change))
+		}
+	}
+
+	if empty {
+		fmt.Fprintln(w, "\nNo differences between the runs")
+	}
+
+	fmt.Fprintf(w, "\nNewly failing: %d | Newly passing: %d | Changed status: %d | Appeared: %d | Disappeared: %d | Slower: %d\n",
+		len(diff.NewlyFailing), len(diff.NewlyPassing), len(diff.Changed), len(diff.Appeared), len(diff.Disappeared), len(diff.Slower))
+}
+
+func describeHeadChange(change testChange) string {
+	return describeResult(change.Head)
+}
+
+func describeBaseChange(change testChange) string {
+	return describeResult(change.Base)
+}
+
+// describeStatus returns the outcome of a test as shown in the diff
+func describeStatus(result *TestResult) string {
+	switch {
+	case result.Failed:
+		return "failed"
+	case result.Skipped:
+		return "skipped"
+	case result.Incomplete:
+		return "incomplete"
+	}
+	return "passed"
+}
+
+func describeResult(result *TestResult) string {
+	parts := []string{describeStatus(result)}
+	if result.Location != "" {
+		parts = append(parts, result.Location)
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func describeStatusChange(change testChange) string {
+	parts := []string{describeStatus(change.Base) + " → " + describeStatus(change.Head)}
+	if change.Head.Location != "" {
+		parts = append(parts, change.Head.Location)
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func describeSlowdown(change testChange) string {
+	return fmt.Sprintf(" (%s → %s, +%s)",
+		formatDuration(change.Base.Elapsed), formatDuration(change.Head.Elapsed),
+		formatDuration(change.Head.Elapsed-change.Base.Elapsed))
+}
+
+// runDiffCommand implements `gotestshow diff base.jsonl head.jsonl`
+func runDiffCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ci := flags.Bool("ci", false, "Disable colors and decorations")
+	threshold := flags.Duration("threshold", 100*time.Millisecond, "Minimum slowdown for a test to count as slower")
+	ratio := flags.Float64("ratio", 1.5, "Minimum head/base elapsed ratio for a test to count as slower")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gotestshow diff [flags] base.jsonl head.jsonl")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Compares two recorded `go test -json` runs and reports tests that")
+		fmt.Fprintln(stderr, "newly fail, newly pass, changed status otherwise (e.g., failed → skipped),")
+		fmt.Fprintln(stderr, "appeared, disappeared, or got slower.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	base, err := loadRecordedRun(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	head, err := loadRecordedRun(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	diff := diffRuns(diffableResults(base), diffableResults(head), diffOptions{Threshold: *threshold, Ratio: *ratio})
+
+	if *ci {
+		fmt.Fprintf(stdout, "Comparing %s -> %s\n", flags.Arg(0), flags.Arg(1))
+	} else {
+		fmt.Fprintf(stdout, "🔍 Comparing %s%s%s → %s%s%s\n", colorBlue, flags.Arg(0), colorReset, colorBlue, flags.Arg(1), colorReset)
+	}
+	renderRunDiff(stdout, diff, *ci)
+
+	if diff.hasNewFailures() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffRuns(t *testing.T) {
+	t.Parallel()
+	base := map[string]*TestResult{
+		"example/TestBroken":   {Package: "example", Test: "TestBroken", Passed: true, Elapsed: 0.1},
+		"example/TestFixed":    {Package: "example", Test: "TestFixed", Failed: true},
+		"example/TestRemoved":  {Package: "example", Test: "TestRemoved", Passed: true},
+		"example/TestSlow":     {Package: "example", Test: "TestSlow", Passed: true, Elapsed: 0.2},
+		"example/TestNoisy":    {Package: "example", Test: "TestNoisy", Passed: true, Elapsed: 0.01},
+		"example/TestParent":   {Package: "example", Test: "TestParent", Passed: true, HasSubtest: true},
+		"example/TestParent/a": {Package: "example", Test: "TestParent/a", Passed: true},
+		"example/TestSkipped":  {Package: "example", Test: "TestSkipped", Failed: true},
+		"other/[BUILD]":        {Package: "other", Test: "[BUILD]", Failed: true},
+	}
+	head := map[string]*TestResult{
+		"example/TestBroken":   {Package: "example", Test: "TestBroken", Failed: true, Elapsed: 0.1},
+		"example/TestFixed":    {Package: "example", Test: "TestFixed", Passed: true},
+		"example/TestAdded":    {Package: "example", Test: "TestAdded", Passed: true},
+		"example/TestSlow":     {Package: "example", Test: "TestSlow", Passed: true, Elapsed: 1.2},
+		"example/TestNoisy":    {Package: "example", Test: "TestNoisy", Passed: true, Elapsed: 0.03},
+		"example/TestParent":   {Package: "example", Test: "TestParent", Passed: true, HasSubtest: true},
+		"example/TestParent/a": {Package: "example", Test: "TestParent/a", Passed: true},
+		"example/TestSkipped":  {Package: "example", Test: "TestSkipped", Skipped: true},
+	}
+
+	diff := diffRuns(base, head, diffOptions{Threshold: 100 * time.Millisecond, Ratio: 1.5})
+
+	names := func(changes []testChange) string {
+		var result []string
+		for _, c := range changes {
+			result = append(result, c.Package+"/"+c.Test)
+		}
+		return strings.Join(result, ",")
+	}
+
+	checks := []struct {
+		category string
+		got      string
+		expected string
+	}{
+		{"newly failing", names(diff.NewlyFailing), "example/TestBroken"},
+		{"newly passing", names(diff.NewlyPassing), "example/TestFixed,other/[BUILD]"},
+		{"changed status", names(diff.Changed), "example/TestSkipped"},
+		{"appeared", names(diff.Appeared), "example/TestAdded"},
+		{"disappeared", names(diff.Disappeared), "example/TestRemoved"},
+		{"slower", names(diff.Slower), "example/TestSlow"},
+	}
+	for _, c := range checks {
+		if c.got != c.expected {
+			t.Errorf("%s = %q, want %q", c.category, c.got, c.expected)
+		}
+	}
+
+	if !diff.hasNewFailures() {
+		t.Error("Expected new failures")
+	}
+}
+
+func TestRenderRunDiff_ChangedStatus(t *testing.T) {
+	t.Parallel()
+	base := map[string]*TestResult{
+		"example/TestA": {Package: "example", Test: "TestA", Failed: true},
+	}
+	head := map[string]*TestResult{
+		"example/TestA": {Package: "example", Test: "TestA", Skipped: true},
+	}
+
+	diff := diffRuns(base, head, diffOptions{Threshold: 100 * time.Millisecond, Ratio: 1.5})
+	var buf bytes.Buffer
+	renderRunDiff(&buf, diff, true)
+
+	output := buf.String()
+	for _, expected := range []string{"Changed status (1)", "TestA example (failed → skipped)", "Changed status: 1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "No differences") {
+		t.Errorf("a changed status is a difference.\nGot:\n%s", output)
+	}
+	if diff.hasNewFailures() {
+		t.Error("a failure that is now skipped is not a new failure")
+	}
+}
+
+func TestRunDiffCommand(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.jsonl")
+	headPath := filepath.Join(dir, "head.jsonl")
+
+	baseRun := `{"Action":"run","Package":"example","Test":"TestA"}
+{"Action":"pass","Package":"example","Test":"TestA","Elapsed":0.01}
+{"Action":"run","Package":"example","Test":"TestB"}
+{"Action":"pass","Package":"example","Test":"TestB","Elapsed":0.01}
+{"Action":"pass","Package":"example","Elapsed":0.02}
+`
+	headRun := `{"Action":"run","Package":"example","Test":"TestA"}
+{"Action":"output","Package":"example","Test":"TestA","Output":"    a_test.go:10: boom\n"}
+{"Action":"fail","Package":"example","Test":"TestA","Elapsed":0.01}
+{"Action":"run","Package":"example","Test":"TestB"}
+{"Action":"pass","Package":"example","Test":"TestB","Elapsed":0.01}
+{"Action":"fail","Package":"example","Elapsed":0.02}
+`
+	if err := os.WriteFile(basePath, []byte(baseRun), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(headPath, []byte(headRun), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runDiffCommand([]string{"-ci", basePath, headPath}, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1 for newly failing tests, got %d (stderr: %s)", exitCode, stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "Newly failing (1)") || !strings.Contains(output, "TestA example (failed, a_test.go:10)") {
+		t.Errorf("Output should list the newly failing test, got:\n%s", output)
+	}
+	if strings.Contains(output, "\033[") {
+		t.Error("CI mode should not contain escape sequences")
+	}
+
+	// Comparing a run with itself shows no differences
+	stdout.Reset()
+	if exitCode := runDiffCommand([]string{"-ci", basePath, basePath}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("Expected exit code 0 for identical runs, got %d", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "No differences") {
+		t.Errorf("Output should report no differences, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunDiffCommand_Usage(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	if exitCode := runDiffCommand([]string{"only-one.jsonl"}, &stdout, &stderr); exitCode != 2 {
+		t.Errorf("Expected exit code 2 for wrong arguments, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Usage: gotestshow diff") {
+		t.Error("Usage should be printed for wrong arguments")
+	}
+
+	if exitCode := runDiffCommand([]string{"missing.jsonl", "missing.jsonl"}, &stdout, &stderr); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for missing files, got %d", exitCode)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,8 +14,10 @@ import (
 type Display interface {
 	ShowProgress(packages map[string]*PackageState, hasTestsStarted bool, startTime time.Time)
 	ShowTestResult(result *TestResult, success bool)
+	ShowTestOutput(result *TestResult, output string)
 	ShowPackageFailure(packageName string, output []string)
-	ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, startTime time.Time) int
+	ShowBenchmarkResults(benchmarks []*BenchmarkResult, deltas []benchmarkDelta)
+	ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta, startTime time.Time) int
 	ShowHelp()
 	ClearLine()
 	SetConfig(config *Config)
@@ -93,6 +96,14 @@ func (d *TerminalDisplay) ShowProgress(packages map[string]*PackageState, hasTes
 		return
 	}
 
+	// A fuzz target runs alone, so its status replaces the counts
+	for _, pkg := range packages {
+		if pkg.Fuzz != nil {
+			d.smartDisplayLine(fmt.Sprintf("%s%s 🎲 %s%s", colorBlue, spinner, formatFuzzProgress(pkg.Fuzz), colorReset))
+			return
+		}
+	}
+
 	totalTests := 0
 	totalPassed := 0
 	totalFailed := 0
@@ -125,6 +136,11 @@ func (d *TerminalDisplay) ShowTestResult(result *TestResult, success bool) {
 		return
 	}
 
+	if success && d.config != nil && d.config.ShowAllOutput {
+		d.showTestResultWithOutput(result)
+		return
+	}
+
 	switch {
 	case d.config != nil && d.config.CIMode:
 		d.showTestResultCI(result, success)
@@ -142,13 +158,17 @@ func (d *TerminalDisplay) showTestResultCI(result *TestResult, success bool) {
 	}
 
 	d.printTestFailureCI(result)
-	d.printTestOutput(result.Output, false)
+	if !d.streamsOutput(result) {
+		d.printTestOutput(result.Output, false)
+	}
+	d.printFuzzInput(result, false)
 }
 
 func (d *TerminalDisplay) showTestResultTiming(result *TestResult, success bool) {
 	// Timing mode: Show slow tests and failures
 	elapsed := formatDuration(result.Elapsed)
-	isSlow := d.isSlowTest(result.Elapsed)
+	threshold, overridden := d.slowThreshold(result)
+	isSlow := exceedsThreshold(result.Elapsed, threshold)
 
 	if !isSlow && !result.Failed {
 		return
@@ -156,15 +176,20 @@ func (d *TerminalDisplay) showTestResultTiming(result *TestResult, success bool)
 
 	icon, color := d.getTestIcon(result)
 	slowIndicator := ""
-	if isSlow {
+	if isSlow && overridden {
+		slowIndicator = fmt.Sprintf(" %s[SLOW >%s]%s", colorRed, threshold, colorReset)
+	} else if isSlow {
 		slowIndicator = fmt.Sprintf(" %s[SLOW]%s", colorRed, colorReset)
 	}
 
 	d.printTestResult(icon, color, result, elapsed, slowIndicator)
 
-	if result.Failed {
+	if result.Failed && !d.streamsOutput(result) {
 		d.printTestOutput(result.Output, true)
 	}
+	if result.Failed {
+		d.printFuzzInput(result, true)
+	}
 }
 
 func (d *TerminalDisplay) showTestResultNormal(result *TestResult, success bool) {
@@ -175,12 +200,76 @@ func (d *TerminalDisplay) showTestResultNormal(result *TestResult, success bool)
 
 	d.ClearLine()
 	d.printTestFailure(result)
-	d.printTestOutput(result.Output, true)
+	if !d.streamsOutput(result) {
+		d.printTestOutput(result.Output, true)
+	}
+	d.printFuzzInput(result, true)
+}
+
+// showTestResultWithOutput shows a passed or skipped test with its output
+// under the same header as failures, for -show-output=all
+func (d *TerminalDisplay) showTestResultWithOutput(result *TestResult) {
+	label := "PASS"
+	if result.Skipped {
+		label = "SKIP"
+	}
+	packageInfo := ""
+	if shouldShowPackageName(d.packages) {
+		packageInfo = fmt.Sprintf(" in %s", result.Package)
+	}
+
+	if d.config.CIMode {
+		fmt.Fprintf(d.writer, "%s %s (%.2fs)%s\n", label, result.Test, result.Elapsed, packageInfo)
+		d.printTestOutput(result.Output, false)
+		return
+	}
+
+	d.ClearLine()
+	icon, color := d.getTestIcon(result)
+	fmt.Fprintf(d.writer, "%s%s %s%s %s %s(%.2fs)%s%s\n",
+		color, icon, label, colorReset, result.Test, colorGray, result.Elapsed, colorReset, packageInfo)
+	d.printTestOutput(result.Output, false)
 }
 
-func (d *TerminalDisplay) isSlowTest(elapsed float64) bool {
-	return d.config.Threshold > 0 &&
-		time.Duration(elapsed*float64(time.Second)) > d.config.Threshold
+// ShowTestOutput streams an output line of a test selected with -show-output,
+// prefixed with the test name since parallel tests interleave
+func (d *TerminalDisplay) ShowTestOutput(result *TestResult, output string) {
+	if !d.streamsOutput(result) {
+		return
+	}
+	relevant := extractRelevantOutput([]string{output})
+	if len(relevant) == 0 {
+		return
+	}
+	line := strings.TrimRight(relevant[0], "\n")
+
+	prefix := result.Test
+	if shouldShowPackageName(d.packages) {
+		prefix = getShortPackageName(result.Package) + " " + result.Test
+	}
+
+	if d.config.CIMode {
+		fmt.Fprintf(d.writer, "%s | %s\n", prefix, line)
+		return
+	}
+	d.ClearLine()
+	fmt.Fprintf(d.writer, "%s%s │%s %s\n", colorGray, prefix, colorReset, line)
+}
+
+// streamsOutput reports whether the output of a test is streamed live by ShowTestOutput
+func (d *TerminalDisplay) streamsOutput(result *TestResult) bool {
+	return d.config != nil && d.config.ShowOutput != nil && d.config.ShowOutput.MatchString(result.Package+"/"+result.Test)
+}
+
+func (d *TerminalDisplay) isSlowTest(result *TestResult) bool {
+	threshold, _ := d.slowThreshold(result)
+	return exceedsThreshold(result.Elapsed, threshold)
+}
+
+// slowThreshold returns the threshold a test is judged against and whether
+// it comes from a threshold override
+func (d *TerminalDisplay) slowThreshold(result *TestResult) (time.Duration, bool) {
+	return thresholdFor(d.config.Thresholds, d.config.Threshold, result.Package, result.Test)
 }
 
 func (d *TerminalDisplay) getTestIcon(result *TestResult) (string, string) {
@@ -262,6 +351,13 @@ func (d *TerminalDisplay) printTestResult(icon, color string, result *TestResult
 }
 
 func (d *TerminalDisplay) printTestOutput(output []string, withColor bool) {
+	if failure, ok := parseExampleFailure(output); ok {
+		if diff, ok := exampleDiff(failure); ok {
+			d.printExampleDiff(failure, diff, withColor)
+			return
+		}
+	}
+
 	relevantOutput := extractRelevantOutput(output)
 	if len(relevantOutput) == 0 {
 		return
@@ -278,6 +374,62 @@ func (d *TerminalDisplay) printTestOutput(output []string, withColor bool) {
 	fmt.Fprintf(d.writer, "\n")
 }
 
+// printExampleDiff shows the output of a failing example as a diff of the
+// expected output against what the example printed
+func (d *TerminalDisplay) printExampleDiff(failure *exampleFailure, diff []string, withColor bool) {
+	header := "Output differs (-want +got):"
+	if failure.Unordered {
+		header = "Unordered output differs (-want +got, lines sorted):"
+	}
+
+	fmt.Fprintf(d.writer, "\n")
+	for _, line := range extractRelevantOutput(failure.Before) {
+		if withColor {
+			fmt.Fprintf(d.writer, "        %s%s%s", colorRed, line, colorReset)
+		} else {
+			fmt.Fprintf(d.writer, "        %s", line)
+		}
+	}
+	if !withColor {
+		fmt.Fprintf(d.writer, "        %s\n", header)
+		for _, line := range diff {
+			fmt.Fprintf(d.writer, "        %s\n", line)
+		}
+		fmt.Fprintf(d.writer, "\n")
+		return
+	}
+
+	fmt.Fprintf(d.writer, "        %s%s%s\n", colorGray, header, colorReset)
+	for _, line := range diff {
+		switch line[0] {
+		case '@':
+			fmt.Fprintf(d.writer, "        %s%s%s\n", colorBlue, line, colorReset)
+		case '-':
+			fmt.Fprintf(d.writer, "        %s%s%s\n", colorRed, line, colorReset)
+		case '+':
+			fmt.Fprintf(d.writer, "        %s%s%s\n", colorGreen, line, colorReset)
+		default:
+			fmt.Fprintf(d.writer, "        %s\n", line)
+		}
+	}
+	fmt.Fprintf(d.writer, "\n")
+}
+
+// printFuzzInput highlights the input a failing fuzz target wrote to its
+// corpus and the command that reruns the target with it
+func (d *TerminalDisplay) printFuzzInput(result *TestResult, withColor bool) {
+	if result.FuzzInput == "" {
+		return
+	}
+	if withColor {
+		fmt.Fprintf(d.writer, "        %s💥 Failing input:%s %s%s%s\n", colorRed, colorReset, colorBlue, result.FuzzInput, colorReset)
+		fmt.Fprintf(d.writer, "        %s↻ Rerun:%s %s\n\n", colorGray, colorReset, fuzzRerunCommand(result))
+	} else {
+		fmt.Fprintf(d.writer, "        Failing input: %s\n", result.FuzzInput)
+		fmt.Fprintf(d.writer, "        Rerun: %s\n\n", fuzzRerunCommand(result))
+	}
+}
+
 // ShowPackageFailure displays package-level failures
 func (d *TerminalDisplay) ShowPackageFailure(packageName string, output []string) {
 	// In CI mode, simple format without colors or escape sequences
@@ -313,27 +465,56 @@ func (d *TerminalDisplay) ShowPackageFailure(packageName string, output []string
 }
 
 // ShowFinalResults displays the final test results summary
-func (d *TerminalDisplay) ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, startTime time.Time) int {
+func (d *TerminalDisplay) ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta, startTime time.Time) int {
+	// Quarantined failures are reported separately and don't count as failures
+	quarantine := applyQuarantine(d.quarantine(), packages, results, time.Now())
+	packages, results = quarantine.packages, quarantine.results
+
+	// Totals and the exit code cover the whole run, the summaries only what the filter shows
 	stats := collectSummaryStats(packages, results)
+	shown := applyDisplayFilter(d.filter(), packages, results)
+	shownStats := collectSummaryStats(shown.packages, shown.results)
 	exitCode := 0
+	if shown.hiddenFailures > 0 {
+		exitCode = 1
+	}
 
 	// In CI mode, show simple summary without colors or decorations
 	if d.config != nil && d.config.CIMode {
 		actualElapsed := time.Since(startTime)
 
+		d.showTopReportCI(shown.packages, shown.results)
+
 		// Show failure summary if there are any failures (without colors/decorations)
-		if stats.hasFailures {
+		if shownStats.hasFailures {
 			fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
 			fmt.Fprintln(d.writer, "Failed Tests Summary")
 			fmt.Fprintln(d.writer, strings.Repeat("=", 50))
 
-			for pkgName, pkg := range packages {
-				if code := d.displayPackageSummaryCI(pkgName, pkg, results); code != 0 {
+			for _, pkgName := range sortPackageNames(shown.packages, shown.results, d.sortOrder()) {
+				if code := d.displayPackageSummaryCI(pkgName, shown.packages[pkgName], shown.results); code != 0 {
 					exitCode = code
 				}
 			}
 
 			fmt.Fprintln(d.writer, "\n"+strings.Repeat("-", 50))
+			d.showRerunCommandsCI(shown.results)
+		}
+
+		d.showCoverageSummary(shown.packages)
+
+		if d.showQuarantineSummaryCI(quarantine) {
+			exitCode = 1
+		}
+
+		d.showIncompleteSummaryCI(packages, results)
+
+		if d.showPolicyViolationsCI(packages, results, deltas) {
+			exitCode = 1
+		}
+
+		if shown.hiddenFailures > 0 {
+			fmt.Fprintf(d.writer, "\n%d hidden failures (not matching -include/-exclude)\n", shown.hiddenFailures)
 		}
 
 		// Simple summary
@@ -341,7 +522,7 @@ func (d *TerminalDisplay) ShowFinalResults(packages map[string]*PackageState, re
 			stats.totalTests, stats.totalPassed, stats.totalFailed, stats.totalSkipped, actualElapsed.Seconds())
 
 		// Final status message
-		if stats.totalFailed > 0 {
+		if exitCode != 0 || stats.totalFailed > 0 {
 			fmt.Fprintf(d.writer, "\nTests failed!\n")
 			return 1
 		} else {
@@ -352,22 +533,42 @@ func (d *TerminalDisplay) ShowFinalResults(packages map[string]*PackageState, re
 
 	// In timing mode, show slow tests summary
 	if d.config != nil && d.config.TimingMode {
-		d.showSlowTestsSummary(results)
+		d.showSlowTestsSummary(shown.results)
 	}
 
+	d.showTopReport(shown.packages, shown.results)
+
 	// Show failure summary if there are any failures
-	if stats.hasFailures {
+	if shownStats.hasFailures {
 		fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
 		fmt.Fprintln(d.writer, "📊 Failed Tests Summary")
 		fmt.Fprintln(d.writer, strings.Repeat("=", 50))
 
-		for pkgName, pkg := range packages {
-			if code := d.displayPackageSummary(pkgName, pkg, results); code != 0 {
+		for _, pkgName := range sortPackageNames(shown.packages, shown.results, d.sortOrder()) {
+			if code := d.displayPackageSummary(pkgName, shown.packages[pkgName], shown.results); code != 0 {
 				exitCode = code
 			}
 		}
 
 		fmt.Fprintln(d.writer, "\n"+strings.Repeat("-", 50))
+		d.showRerunCommands(shown.results)
+	}
+
+	d.showCoverageSummary(shown.packages)
+
+	if d.showQuarantineSummary(quarantine) {
+		exitCode = 1
+	}
+
+	d.showIncompleteSummary(packages, results)
+
+	if d.showPolicyViolations(packages, results, deltas) {
+		exitCode = 1
+	}
+
+	if shown.hiddenFailures > 0 {
+		fmt.Fprintf(d.writer, "\n%s⚠️  %d hidden failures%s %s(not matching -include/-exclude)%s\n",
+			colorYellow, shown.hiddenFailures, colorReset, colorGray, colorReset)
 	}
 
 	// Overall summary
@@ -389,20 +590,234 @@ func (d *TerminalDisplay) ShowFinalResults(packages map[string]*PackageState, re
 	}
 }
 
+// ShowBenchmarkResults displays a table of benchmark results grouped by
+// package, followed by the comparison against the baseline when one is set
+func (d *TerminalDisplay) ShowBenchmarkResults(benchmarks []*BenchmarkResult, deltas []benchmarkDelta) {
+	if len(benchmarks) == 0 {
+		return
+	}
+
+	ciMode := d.config != nil && d.config.CIMode
+	grouped := summarizeBenchmarks(benchmarks)
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	if ciMode {
+		fmt.Fprintln(d.writer, "Benchmarks")
+	} else {
+		fmt.Fprintln(d.writer, "📈 Benchmarks")
+	}
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	packageNames := make([]string, 0, len(grouped))
+	for pkgName := range grouped {
+		packageNames = append(packageNames, pkgName)
+	}
+	sort.Strings(packageNames)
+
+	for _, pkgName := range packageNames {
+		lines := benchmarkTableLines(grouped[pkgName])
+		shortPkg := getShortPackageName(pkgName)
+
+		if ciMode {
+			fmt.Fprintf(d.writer, "\n=== %s ===\n", shortPkg)
+			for _, line := range lines {
+				fmt.Fprintf(d.writer, "  %s\n", line)
+			}
+			continue
+		}
+
+		fmt.Fprintf(d.writer, "\n=== %s%s%s ===\n", colorBlue, shortPkg, colorReset)
+		fmt.Fprintf(d.writer, "  %s%s%s\n", colorGray, lines[0], colorReset)
+		for _, line := range lines[1:] {
+			fmt.Fprintf(d.writer, "  %s\n", line)
+		}
+	}
+
+	if d.config != nil && d.config.BenchBaseline != nil {
+		d.showBenchmarkComparison(deltas)
+	}
+}
+
+// showBenchmarkComparison displays a benchstat-style table of changes against the baseline
+func (d *TerminalDisplay) showBenchmarkComparison(deltas []benchmarkDelta) {
+	ciMode := d.config.CIMode
+	regressions := countRegressions(deltas)
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	if ciMode {
+		fmt.Fprintln(d.writer, "Benchmark Comparison (vs baseline)")
+	} else {
+		fmt.Fprintln(d.writer, "⚖️  Benchmark Comparison (vs baseline)")
+	}
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	if len(deltas) == 0 {
+		fmt.Fprintln(d.writer, "\nNo benchmarks in common with the baseline")
+		return
+	}
+
+	byPackage := make(map[string][]benchmarkDelta)
+	var packageNames []string
+	for _, delta := range deltas {
+		if _, exists := byPackage[delta.Package]; !exists {
+			packageNames = append(packageNames, delta.Package)
+		}
+		byPackage[delta.Package] = append(byPackage[delta.Package], delta)
+	}
+	sort.Strings(packageNames)
+
+	for _, pkgName := range packageNames {
+		pkgDeltas := byPackage[pkgName]
+		rows := [][]string{{"Benchmark", "Unit", "Old", "New", "Delta", ""}}
+		for _, delta := range pkgDeltas {
+			rows = append(rows, []string{
+				delta.Name, delta.Unit,
+				formatBenchValue(delta.OldMean), formatBenchValue(delta.NewMean),
+				formatDeltaPercent(delta.DeltaPercent), formatSignificance(delta),
+			})
+		}
+		lines := formatTable(rows)
+
+		shortPkg := getShortPackageName(pkgName)
+		if ciMode {
+			fmt.Fprintf(d.writer, "\n=== %s ===\n", shortPkg)
+			fmt.Fprintf(d.writer, "  %s\n", lines[0])
+			for i, delta := range pkgDeltas {
+				marker := ""
+				if delta.Regression {
+					marker = " REGRESSION"
+				}
+				fmt.Fprintf(d.writer, "  %s%s\n", lines[i+1], marker)
+			}
+			continue
+		}
+
+		fmt.Fprintf(d.writer, "\n=== %s%s%s ===\n", colorBlue, shortPkg, colorReset)
+		fmt.Fprintf(d.writer, "  %s%s%s\n", colorGray, lines[0], colorReset)
+		for i, delta := range pkgDeltas {
+			switch {
+			case delta.Regression:
+				fmt.Fprintf(d.writer, "  %s%s ✗ regression%s\n", colorRed, lines[i+1], colorReset)
+			case delta.Improvement:
+				fmt.Fprintf(d.writer, "  %s%s%s\n", colorGreen, lines[i+1], colorReset)
+			default:
+				fmt.Fprintf(d.writer, "  %s\n", lines[i+1])
+			}
+		}
+	}
+
+	if regressions > 0 {
+		if ciMode {
+			fmt.Fprintf(d.writer, "\n%d benchmark metrics regressed by more than %.1f%%\n", regressions, d.config.BenchRegression)
+		} else {
+			fmt.Fprintf(d.writer, "\n%s🐌 %d benchmark metrics regressed by more than %.1f%%%s\n",
+				colorRed, regressions, d.config.BenchRegression, colorReset)
+		}
+	}
+}
+
+// benchmarkTableLines renders the benchmark summaries of a package as aligned
+// lines, starting with a header line
+func benchmarkTableLines(summaries []*benchmarkSummary) []string {
+	units := benchmarkUnits(summaries)
+
+	multipleSamples := false
+	for _, s := range summaries {
+		if s.Samples > 1 {
+			multipleSamples = true
+		}
+	}
+
+	header := []string{"Benchmark"}
+	if multipleSamples {
+		header = append(header, "n")
+	}
+	header = append(header, "Iterations")
+	header = append(header, units...)
+	rows := [][]string{header}
+
+	for _, s := range summaries {
+		row := []string{s.Name}
+		if multipleSamples {
+			row = append(row, strconv.Itoa(s.Samples))
+		}
+		row = append(row, strconv.FormatInt(s.Iterations, 10))
+		for _, unit := range units {
+			if value, exists := s.Metrics[unit]; exists {
+				row = append(row, formatBenchValue(value))
+			} else {
+				row = append(row, "-")
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return formatTable(rows)
+}
+
 // ShowHelp displays the help message
 func (d *TerminalDisplay) ShowHelp() {
 	fmt.Fprintln(d.writer, "gotestshow - A real-time formatter for `go test -json` output")
 	fmt.Fprintln(d.writer)
 	fmt.Fprintln(d.writer, "Usage:")
 	fmt.Fprintln(d.writer, "  go test -json ./... | gotestshow [flags]")
+	fmt.Fprintln(d.writer, "  gotestshow -changed[=ref] [flags] [packages]")
+	fmt.Fprintln(d.writer, "  gotestshow watch [flags] [packages]")
+	fmt.Fprintln(d.writer, "  gotestshow diff [flags] base.jsonl head.jsonl")
+	fmt.Fprintln(d.writer, "  gotestshow coverdiff [flags] cover.out...")
+	fmt.Fprintln(d.writer, "  gotestshow config [flags]")
 	fmt.Fprintln(d.writer)
 	fmt.Fprintln(d.writer, "Flags:")
 	fmt.Fprintln(d.writer, "  -timing         Enable timing mode to show only slow tests and failures")
 	fmt.Fprintln(d.writer, "  -threshold      Threshold for slow tests (default: 500ms)")
 	fmt.Fprintln(d.writer, "                  Examples: 1s, 500ms, 1.5s")
+	fmt.Fprintln(d.writer, "  -thresholds     JSON file with per-package and per-test slow thresholds")
 	fmt.Fprintln(d.writer, "  -ci             Enable CI mode - no escape sequences, only show failures and summary")
+	fmt.Fprintln(d.writer, "  -tui            Browse the results in an interactive full-screen view once the run completes")
+	fmt.Fprintln(d.writer, "  -changed[=ref]  Run go test for the packages affected by changes since ref (default: merge-base with main)")
+	fmt.Fprintln(d.writer, "  -coverprofile   With -changed or watch, collect the coverage profiles of go test into this file")
+	fmt.Fprintln(d.writer, "  -watch-interval How often watch mode polls the module's .go files for changes (default: 500ms)")
+	fmt.Fprintln(d.writer, "  -include        Only display tests whose package/test matches this regexp")
+	fmt.Fprintln(d.writer, "  -exclude        Don't display tests whose package/test matches this regexp")
+	fmt.Fprintln(d.writer, "  -show-output    Stream the output of tests whose package/test matches a regexp, or \"all\" for every test")
+	fmt.Fprintln(d.writer, "  -sort           Order of packages and failed tests in the summary: name, duration or status (default: name)")
+	fmt.Fprintln(d.writer, "  -top            Show the N slowest tests, packages and test trees at the end")
+	fmt.Fprintln(d.writer, "  -history        File recording test durations across runs; timing mode reports tests that got slower")
+	fmt.Fprintln(d.writer, "  -history-factor Factor over the historical median that counts as slower (default: 2)")
+	fmt.Fprintln(d.writer, "  -rerun-script   Write commands to rerun failed tests to the given shell script")
+	fmt.Fprintln(d.writer, "  -trace          Write a Chrome trace-event file of the run for Perfetto or chrome://tracing")
+	fmt.Fprintln(d.writer, "  -html           Write a self-contained HTML report of the run to the given file")
+	fmt.Fprintln(d.writer, "  -markdown       Write a Markdown summary of the run to the given file")
+	fmt.Fprintln(d.writer, "  -step-summary   Append a Markdown summary to $GITHUB_STEP_SUMMARY when set (default: true)")
+	fmt.Fprintln(d.writer, "  -tap            Write the results as TAP to the given file (\"-\" for stdout, the display goes to stderr)")
+	fmt.Fprintln(d.writer, "  -tap-version    TAP version to write, 13 or 14 (default: 14)")
+	fmt.Fprintln(d.writer, "  -jsonfile       Copy every received line to the given file (\"-\" for stdout, the display goes to stderr)")
+	fmt.Fprintln(d.writer, "  -jsonfile-format raw (default) copies the input, enriched annotates events with location, quarantine and flaky status")
+	fmt.Fprintln(d.writer, "  -quarantine     JSON file listing known-broken tests that don't fail the build")
+	fmt.Fprintln(d.writer, "  -config         Configuration file (default: .gotestshow.{yaml,toml,json} found upwards)")
 	fmt.Fprintln(d.writer, "  -help           Show this help message")
 	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "Benchmarks:")
+	fmt.Fprintln(d.writer, "  -bench-save                Save this run's benchmark results to a file")
+	fmt.Fprintln(d.writer, "  -bench-baseline            Compare benchmarks against a saved file or recorded go test -json stream")
+	fmt.Fprintln(d.writer, "  -bench-regression          Percentage change counted as a regression (default: 10)")
+	fmt.Fprintln(d.writer, "  -bench-fail-on-regression  Fail if any benchmark regressed against the baseline")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "Exit code policy:")
+	fmt.Fprintln(d.writer, "  -fail-on-no-tests       Fail if no tests were run")
+	fmt.Fprintln(d.writer, "  -fail-on-empty-package  Fail if any package has no tests")
+	fmt.Fprintln(d.writer, "  -fail-on-skip           Fail if a skipped test name matches this regexp")
+	fmt.Fprintln(d.writer, "  -min-coverage           Fail if a package's coverage is below PERCENT or PATTERN=PERCENT entries (e.g., 'api/.*=90,70')")
+	fmt.Fprintln(d.writer, "  -max-slow               Fail if more than N tests exceed -threshold")
+	fmt.Fprintln(d.writer, "  -fail-on-truncated      Fail if the input ended before a package reported its result (default: true)")
+	fmt.Fprintln(d.writer, "  -allow-incomplete       Don't fail if the input ended before every package reported its result")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "Configuration:")
+	fmt.Fprintln(d.writer, "  Every flag can also be set in a .gotestshow.yaml, .gotestshow.toml or")
+	fmt.Fprintln(d.writer, "  .gotestshow.json file, or with a GOTESTSHOW_<FLAG> environment variable")
+	fmt.Fprintln(d.writer, "  (e.g., GOTESTSHOW_THRESHOLD=1s). Flags override the environment, which")
+	fmt.Fprintln(d.writer, "  overrides the file. `gotestshow config` prints the effective settings.")
+	fmt.Fprintln(d.writer)
 	fmt.Fprintln(d.writer, "Description:")
 	fmt.Fprintln(d.writer, "  gotestshow reads JSON-formatted test output from stdin and displays")
 	fmt.Fprintln(d.writer, "  it in a human-readable format with real-time progress updates.")
@@ -420,6 +835,21 @@ func (d *TerminalDisplay) ShowHelp() {
 	fmt.Fprintln(d.writer)
 	fmt.Fprintln(d.writer, "  # Enable timing mode with custom threshold")
 	fmt.Fprintln(d.writer, "  go test -json ./... | gotestshow -timing -threshold=1s")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "  # Test the packages affected by the changes on this branch")
+	fmt.Fprintln(d.writer, "  gotestshow -changed")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "  # Rerun affected packages whenever a .go file changes")
+	fmt.Fprintln(d.writer, "  gotestshow watch ./...")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "  # Show the effective settings")
+	fmt.Fprintln(d.writer, "  gotestshow config")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "  # Show changed lines no test covers, failing below 80%")
+	fmt.Fprintln(d.writer, "  gotestshow coverdiff -min 80 cover.out")
+	fmt.Fprintln(d.writer)
+	fmt.Fprintln(d.writer, "  # Compare two recorded runs")
+	fmt.Fprintln(d.writer, "  gotestshow diff main.jsonl branch.jsonl")
 }
 
 // ClearLine clears the current line
@@ -488,8 +918,8 @@ func (d *TerminalDisplay) displayPackageSummary(pkgName string, pkg *PackageStat
 	}
 
 	// Display test statistics
-	fmt.Fprintf(d.writer, "  Tests: %d | Passed: %d | Failed: %d | Skipped: %d\n",
-		pkg.Total, pkg.Passed, pkg.Failed, pkg.Skipped)
+	fmt.Fprintf(d.writer, "  Tests: %d | Passed: %d | Failed: %d | Skipped: %d%s\n",
+		pkg.Total, pkg.Passed, pkg.Failed, pkg.Skipped, coverageSuffix(pkg))
 
 	// Display individual test failures
 	if pkg.Failed > 0 {
@@ -500,25 +930,43 @@ func (d *TerminalDisplay) displayPackageSummary(pkgName string, pkg *PackageStat
 	return exitCode
 }
 
-func (d *TerminalDisplay) displayFailedTestsFor(pkgName string, pkg *PackageState, results map[string]*TestResult) {
+// coverageSuffix appends the coverage of a package to its test statistics
+func coverageSuffix(pkg *PackageState) string {
+	if !pkg.HasCoverage {
+		return ""
+	}
+	return " | Coverage: " + formatCoverage(pkg.Coverage)
+}
+
+// failedTestsFor returns the failed tests and build failure of a package in the sort order
+func (d *TerminalDisplay) failedTestsFor(pkgName string, results map[string]*TestResult) []*TestResult {
+	var failed []*TestResult
 	for _, result := range results {
 		if result.Package == pkgName && result.Failed && result.Test != "[PACKAGE]" && !result.HasSubtest {
-			if result.Test == "[BUILD]" {
-				if result.Location != "" {
-					fmt.Fprintf(d.writer, "    %s✗ BUILD FAIL%s %s[%s]%s\n",
-						colorRed, colorReset, colorBlue, result.Location, colorReset)
-				} else {
-					fmt.Fprintf(d.writer, "    %s✗ BUILD FAIL%s\n",
-						colorRed, colorReset)
-				}
+			failed = append(failed, result)
+		}
+	}
+	sortTestResults(failed, d.sortOrder())
+	return failed
+}
+
+func (d *TerminalDisplay) displayFailedTestsFor(pkgName string, pkg *PackageState, results map[string]*TestResult) {
+	for _, result := range d.failedTestsFor(pkgName, results) {
+		if result.Test == "[BUILD]" {
+			if result.Location != "" {
+				fmt.Fprintf(d.writer, "    %s✗ BUILD FAIL%s %s[%s]%s\n",
+					colorRed, colorReset, colorBlue, result.Location, colorReset)
 			} else {
-				if result.Location != "" {
-					fmt.Fprintf(d.writer, "    %s✗ %s%s %s[%s]%s %s(%.2fs)%s\n",
-						colorRed, result.Test, colorReset, colorBlue, result.Location, colorReset, colorGray, result.Elapsed, colorReset)
-				} else {
-					fmt.Fprintf(d.writer, "    %s✗ %s%s %s(%.2fs)%s\n",
-						colorRed, result.Test, colorReset, colorGray, result.Elapsed, colorReset)
-				}
+				fmt.Fprintf(d.writer, "    %s✗ BUILD FAIL%s\n",
+					colorRed, colorReset)
+			}
+		} else {
+			if result.Location != "" {
+				fmt.Fprintf(d.writer, "    %s✗ %s%s %s[%s]%s %s(%.2fs)%s\n",
+					colorRed, result.Test, colorReset, colorBlue, result.Location, colorReset, colorGray, result.Elapsed, colorReset)
+			} else {
+				fmt.Fprintf(d.writer, "    %s✗ %s%s %s(%.2fs)%s\n",
+					colorRed, result.Test, colorReset, colorGray, result.Elapsed, colorReset)
 			}
 		}
 	}
@@ -563,8 +1011,8 @@ func (d *TerminalDisplay) displayPackageSummaryCI(pkgName string, pkg *PackageSt
 	}
 
 	// Display test statistics
-	fmt.Fprintf(d.writer, "  Tests: %d | Passed: %d | Failed: %d | Skipped: %d\n",
-		pkg.Total, pkg.Passed, pkg.Failed, pkg.Skipped)
+	fmt.Fprintf(d.writer, "  Tests: %d | Passed: %d | Failed: %d | Skipped: %d%s\n",
+		pkg.Total, pkg.Passed, pkg.Failed, pkg.Skipped, coverageSuffix(pkg))
 
 	// Display individual test failures
 	if pkg.Failed > 0 {
@@ -576,22 +1024,20 @@ func (d *TerminalDisplay) displayPackageSummaryCI(pkgName string, pkg *PackageSt
 }
 
 func (d *TerminalDisplay) displayFailedTestsForCI(pkgName string, pkg *PackageState, results map[string]*TestResult) {
-	for _, result := range results {
-		if result.Package == pkgName && result.Failed && result.Test != "[PACKAGE]" && !result.HasSubtest {
-			if result.Test == "[BUILD]" {
-				if result.Location != "" {
-					fmt.Fprintf(d.writer, "    BUILD FAIL [%s]\n", result.Location)
-				} else {
-					fmt.Fprintf(d.writer, "    BUILD FAIL\n")
-				}
+	for _, result := range d.failedTestsFor(pkgName, results) {
+		if result.Test == "[BUILD]" {
+			if result.Location != "" {
+				fmt.Fprintf(d.writer, "    BUILD FAIL [%s]\n", result.Location)
 			} else {
-				if result.Location != "" {
-					fmt.Fprintf(d.writer, "    FAIL %s [%s] (%.2fs)\n",
-						result.Test, result.Location, result.Elapsed)
-				} else {
-					fmt.Fprintf(d.writer, "    FAIL %s (%.2fs)\n",
-						result.Test, result.Elapsed)
-				}
+				fmt.Fprintf(d.writer, "    BUILD FAIL\n")
+			}
+		} else {
+			if result.Location != "" {
+				fmt.Fprintf(d.writer, "    FAIL %s [%s] (%.2fs)\n",
+					result.Test, result.Location, result.Elapsed)
+			} else {
+				fmt.Fprintf(d.writer, "    FAIL %s (%.2fs)\n",
+					result.Test, result.Elapsed)
 			}
 		}
 	}
@@ -605,7 +1051,8 @@ func extractRelevantOutput(output []string) []string {
 		if trimmed != "" &&
 			!strings.HasPrefix(trimmed, "=== RUN") &&
 			!strings.HasPrefix(trimmed, "=== PAUSE") &&
-			!strings.HasPrefix(trimmed, "=== CONT") {
+			!strings.HasPrefix(trimmed, "=== CONT") &&
+			!isFuzzStatusLine(trimmed) {
 			relevant = append(relevant, line)
 		}
 	}
@@ -661,10 +1108,313 @@ func shouldShowPackageName(packages map[string]*PackageState) bool {
 	return packageCount > 1
 }
 
+func (d *TerminalDisplay) quarantine() *Quarantine {
+	if d.config == nil {
+		return nil
+	}
+	return d.config.Quarantine
+}
+
+// showQuarantineSummary displays quarantined failures and stale quarantine entries.
+// It returns true if the run must fail because of expired entries.
+func (d *TerminalDisplay) showQuarantineSummary(report quarantineReport) bool {
+	if !report.hasFindings() {
+		return false
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(d.writer, "🔒 Quarantined")
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	if len(report.quarantined) > 0 {
+		fmt.Fprintln(d.writer)
+		for _, q := range report.quarantined {
+			location := ""
+			if q.result.Location != "" {
+				location = fmt.Sprintf(" %s[%s]%s", colorBlue, q.result.Location, colorReset)
+			}
+			fmt.Fprintf(d.writer, "    %s✗ %s%s%s %s(%.2fs)%s %s%s\n",
+				colorYellow, q.result.Test, colorReset, location, colorGray, q.result.Elapsed, colorReset,
+				getShortPackageName(q.result.Package), formatQuarantineReason(q.entry))
+		}
+	}
+
+	if len(report.unexpectedlyPass) > 0 {
+		fmt.Fprintf(d.writer, "\n%s⚠ Quarantined tests passed - remove these entries:%s\n", colorYellow, colorReset)
+		for _, entry := range report.unexpectedlyPass {
+			fmt.Fprintf(d.writer, "    %s%s\n", entry.describe(), formatQuarantineReason(entry))
+		}
+	}
+
+	if len(report.expired) > 0 {
+		fmt.Fprintf(d.writer, "\n%s✗ Expired quarantine entries:%s\n", colorRed, colorReset)
+		for _, entry := range report.expired {
+			fmt.Fprintf(d.writer, "    %s%s%s (expired %s)%s\n",
+				colorRed, entry.describe(), formatQuarantineReason(entry), entry.Expires, colorReset)
+		}
+	}
+
+	return len(report.expired) > 0
+}
+
+func (d *TerminalDisplay) showQuarantineSummaryCI(report quarantineReport) bool {
+	if !report.hasFindings() {
+		return false
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(d.writer, "Quarantined")
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	if len(report.quarantined) > 0 {
+		fmt.Fprintln(d.writer)
+		for _, q := range report.quarantined {
+			location := ""
+			if q.result.Location != "" {
+				location = fmt.Sprintf(" [%s]", q.result.Location)
+			}
+			fmt.Fprintf(d.writer, "    QUARANTINED %s%s (%.2fs) %s%s\n",
+				q.result.Test, location, q.result.Elapsed,
+				getShortPackageName(q.result.Package), formatQuarantineReason(q.entry))
+		}
+	}
+
+	if len(report.unexpectedlyPass) > 0 {
+		fmt.Fprintf(d.writer, "\nQuarantined tests passed - remove these entries:\n")
+		for _, entry := range report.unexpectedlyPass {
+			fmt.Fprintf(d.writer, "    %s%s\n", entry.describe(), formatQuarantineReason(entry))
+		}
+	}
+
+	if len(report.expired) > 0 {
+		fmt.Fprintf(d.writer, "\nExpired quarantine entries:\n")
+		for _, entry := range report.expired {
+			fmt.Fprintf(d.writer, "    %s%s (expired %s)\n", entry.describe(), formatQuarantineReason(entry), entry.Expires)
+		}
+	}
+
+	return len(report.expired) > 0
+}
+
+func formatQuarantineReason(entry *QuarantineEntry) string {
+	if entry.Reason == "" {
+		return ""
+	}
+	return " - " + entry.Reason
+}
+
+func (d *TerminalDisplay) sortOrder() SortOrder {
+	if d.config == nil || d.config.Sort == "" {
+		return SortByName
+	}
+	return d.config.Sort
+}
+
+func (d *TerminalDisplay) filter() *DisplayFilter {
+	if d.config == nil {
+		return nil
+	}
+	return d.config.Filter
+}
+
+// collectIncomplete returns incomplete packages (sorted) and their incomplete tests
+func collectIncomplete(packages map[string]*PackageState, results map[string]*TestResult) ([]string, map[string][]*TestResult) {
+	tests := make(map[string][]*TestResult)
+	for _, result := range sortedResults(results) {
+		if result.Incomplete && !result.HasSubtest {
+			tests[result.Package] = append(tests[result.Package], result)
+		}
+	}
+
+	var names []string
+	for _, name := range sortedPackageNames(packages) {
+		if packages[name].Incomplete || len(tests[name]) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names, tests
+}
+
+// showIncompleteSummary displays packages and tests cut off by the end of
+// input; whether they fail the run is up to the truncated policy rule.
+// It returns true if anything was incomplete.
+func (d *TerminalDisplay) showIncompleteSummary(packages map[string]*PackageState, results map[string]*TestResult) bool {
+	names, tests := collectIncomplete(packages, results)
+	if len(names) == 0 {
+		return false
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(d.writer, "⚠️  Incomplete (input ended before these finished)")
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	for _, name := range names {
+		fmt.Fprintf(d.writer, "\n%s? INCOMPLETE%s %s\n", colorYellow, colorReset, getShortPackageName(name))
+		for _, result := range tests[name] {
+			fmt.Fprintf(d.writer, "    %s? %s%s (still running)\n", colorYellow, result.Test, colorReset)
+		}
+	}
+	return true
+}
+
+func (d *TerminalDisplay) showIncompleteSummaryCI(packages map[string]*PackageState, results map[string]*TestResult) bool {
+	names, tests := collectIncomplete(packages, results)
+	if len(names) == 0 {
+		return false
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(d.writer, "Incomplete (input ended before these finished)")
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	for _, name := range names {
+		fmt.Fprintf(d.writer, "\nINCOMPLETE %s\n", getShortPackageName(name))
+		for _, result := range tests[name] {
+			fmt.Fprintf(d.writer, "    INCOMPLETE %s (still running)\n", result.Test)
+		}
+	}
+	return true
+}
+
+func (d *TerminalDisplay) policyViolations(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta) []policyViolation {
+	if d.config == nil {
+		return DefaultPolicy().Evaluate(packages, results, deltas, 0, nil)
+	}
+
+	policy := DefaultPolicy()
+	if d.config.Policy != nil {
+		policy = *d.config.Policy
+	}
+	return policy.Evaluate(packages, results, deltas, d.config.Threshold, d.config.Thresholds)
+}
+
+// showPolicyViolations lists every violated exit-code rule.
+// It returns true if any rule was violated.
+func (d *TerminalDisplay) showPolicyViolations(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta) bool {
+	violations := d.policyViolations(packages, results, deltas)
+	if len(violations) == 0 {
+		return false
+	}
+
+	fmt.Fprintf(d.writer, "\n%s🚫 Policy violations:%s\n", colorRed, colorReset)
+	for _, v := range violations {
+		fmt.Fprintf(d.writer, "    %s✗ [%s]%s %s\n", colorRed, v.Rule, colorReset, v.Detail)
+	}
+	return true
+}
+
+func (d *TerminalDisplay) showPolicyViolationsCI(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta) bool {
+	violations := d.policyViolations(packages, results, deltas)
+	if len(violations) == 0 {
+		return false
+	}
+
+	fmt.Fprintf(d.writer, "\nPolicy violations:\n")
+	for _, v := range violations {
+		fmt.Fprintf(d.writer, "    [%s] %s\n", v.Rule, v.Detail)
+	}
+	return true
+}
+
+// coverageMinimum returns the -min-coverage minimum for a package, if any
+func (d *TerminalDisplay) coverageMinimum(packageName string) (float64, bool) {
+	if d.config == nil || d.config.Policy == nil {
+		return 0, false
+	}
+	return coverageMinimum(d.config.Policy.MinCoverage, packageName)
+}
+
+// coveredPackages returns the names of packages with reported coverage
+func coveredPackages(packages map[string]*PackageState) []string {
+	var names []string
+	for _, name := range sortedPackageNames(packages) {
+		if packages[name].HasCoverage {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// showCoverageSummary lists the coverage of every package next to its test
+// count, marking packages below their -min-coverage minimum
+func (d *TerminalDisplay) showCoverageSummary(packages map[string]*PackageState) {
+	names := coveredPackages(packages)
+	if len(names) == 0 {
+		return
+	}
+
+	ciMode := d.config != nil && d.config.CIMode
+	width := 0
+	for _, name := range names {
+		width = max(width, len(getShortPackageName(name)))
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	if ciMode {
+		fmt.Fprintln(d.writer, "Coverage")
+	} else {
+		fmt.Fprintln(d.writer, "🧪 Coverage")
+	}
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	for _, name := range names {
+		pkg := packages[name]
+		minimum, hasMinimum := d.coverageMinimum(name)
+		below := hasMinimum && pkg.Coverage < minimum
+		coverage := fmt.Sprintf("%6s", formatCoverage(pkg.Coverage))
+
+		note := ""
+		if ciMode {
+			if hasMinimum {
+				note = fmt.Sprintf(" (min %s)", formatCoverage(minimum))
+			}
+			if below {
+				note += " BELOW MINIMUM"
+			}
+		} else {
+			color := colorGreen
+			if below {
+				color = colorRed
+			}
+			coverage = color + coverage + colorReset
+			if hasMinimum {
+				note = fmt.Sprintf(" %s(min %s)%s", colorGray, formatCoverage(minimum), colorReset)
+			}
+		}
+		fmt.Fprintf(d.writer, "  %-*s  %5d tests  %s%s\n", width, getShortPackageName(name), pkg.Total, coverage, note)
+	}
+}
+
+// showRerunCommands displays copy-pasteable commands to rerun failed tests
+func (d *TerminalDisplay) showRerunCommands(results map[string]*TestResult) {
+	commands := buildRerunCommands(results)
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Fprintf(d.writer, "\n🔁 Rerun failed tests:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(d.writer, "  %s%s%s\n", colorBlue, cmd.Command, colorReset)
+	}
+}
+
+func (d *TerminalDisplay) showRerunCommandsCI(results map[string]*TestResult) {
+	commands := buildRerunCommands(results)
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Fprintf(d.writer, "\nRerun failed tests:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(d.writer, "  %s\n", cmd.Command)
+	}
+}
+
 type slowTest struct {
-	name     string
-	elapsed  float64
-	location string
+	name      string
+	elapsed   float64
+	location  string
+	threshold string // Overridden threshold the test was judged against (empty for the default)
 }
 
 // showSlowTestsSummary displays a summary of slow tests
@@ -677,42 +1427,174 @@ func (d *TerminalDisplay) showSlowTestsSummary(results map[string]*TestResult) {
 			continue
 		}
 
-		if d.isSlowTest(result.Elapsed) {
+		threshold, overridden := d.slowThreshold(result)
+		if exceedsThreshold(result.Elapsed, threshold) {
 			test := slowTest{
 				name:     result.Test,
 				elapsed:  result.Elapsed,
 				location: result.Location,
 			}
+			if overridden {
+				test.threshold = threshold.String()
+			}
 			slowTestsByPackage[result.Package] = append(slowTestsByPackage[result.Package], test)
 		}
 	}
 
-	if len(slowTestsByPackage) == 0 {
+	if len(slowTestsByPackage) > 0 {
+		// Display header
+		fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+		fmt.Fprintf(d.writer, "🐢 Slow Tests (>%s)\n", d.config.Threshold)
+		fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+		packageNames := make([]string, 0, len(slowTestsByPackage))
+		for pkgName, tests := range slowTestsByPackage {
+			// Sort by elapsed time (slowest first)
+			sort.SliceStable(tests, func(i, j int) bool {
+				if tests[i].elapsed != tests[j].elapsed {
+					return tests[i].elapsed > tests[j].elapsed
+				}
+				return tests[i].name < tests[j].name
+			})
+			packageNames = append(packageNames, pkgName)
+		}
+		// All of them are slow, so only sorting by duration changes the
+		// package order: the package with the slowest test comes first
+		sort.Slice(packageNames, func(i, j int) bool {
+			a, b := slowTestsByPackage[packageNames[i]][0], slowTestsByPackage[packageNames[j]][0]
+			if d.sortOrder() == SortByDuration && a.elapsed != b.elapsed {
+				return a.elapsed > b.elapsed
+			}
+			return packageNames[i] < packageNames[j]
+		})
+
+		// Display tests by package
+		for _, pkgName := range packageNames {
+			d.displaySlowTestsForPackage(pkgName, slowTestsByPackage[pkgName])
+		}
+	}
+
+	d.showGotSlowerSummary(results)
+}
+
+// showGotSlowerSummary displays tests that took much longer than their
+// historical median, even if they are below the slow test threshold
+func (d *TerminalDisplay) showGotSlowerSummary(results map[string]*TestResult) {
+	regressions := findHistoryRegressions(d.config.History, results, d.config.HistoryFactor)
+	if len(regressions) == 0 {
 		return
 	}
 
-	// Display header
 	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
-	fmt.Fprintf(d.writer, "🐢 Slow Tests (>%s)\n", d.config.Threshold)
+	fmt.Fprintf(d.writer, "📉 Got Slower (>%.1fx historical median)\n", d.config.HistoryFactor)
 	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+	fmt.Fprintln(d.writer)
 
-	// Display tests by package
-	for pkgName, tests := range slowTestsByPackage {
-		// Sort by elapsed time (slowest first)
-		sort.Slice(tests, func(i, j int) bool {
-			return tests[i].elapsed > tests[j].elapsed
-		})
+	for _, r := range regressions {
+		packageInfo := ""
+		if shouldShowPackageName(d.packages) {
+			packageInfo = fmt.Sprintf(" %s%s%s", colorGray, getShortPackageName(r.result.Package), colorReset)
+		}
+		ratio := ""
+		if r.median > 0 {
+			ratio = fmt.Sprintf("%.1fx, ", r.result.Elapsed/r.median)
+		}
+		fmt.Fprintf(d.writer, "  %s %s%s → %s%s %s(%smedian of %d runs)%s%s\n",
+			r.result.Test,
+			colorGray, formatDuration(r.median), colorRed, formatDuration(r.result.Elapsed),
+			colorGray, ratio, r.samples, colorReset, packageInfo)
+	}
+}
+
+// showTopReport displays the slowest tests, packages and test trees
+func (d *TerminalDisplay) showTopReport(packages map[string]*PackageState, results map[string]*TestResult) {
+	report := d.topReport(packages, results)
+	if report.isEmpty() {
+		return
+	}
 
-		d.displaySlowTestsForPackage(pkgName, tests)
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintf(d.writer, "🏆 Top %d\n", d.config.Top)
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	showPackage := shouldShowPackageName(packages)
+	for _, section := range report.sections() {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(d.writer, "\n%s%s%s\n", colorBlue, section.title, colorReset)
+		for i, line := range rankedLines(section.entries) {
+			packageInfo := ""
+			if showPackage && section.entries[i].Name != "" {
+				packageInfo = fmt.Sprintf("  %s%s%s", colorGray, getShortPackageName(section.entries[i].Package), colorReset)
+			}
+			fmt.Fprintf(d.writer, "  %s%s\n", line, packageInfo)
+		}
 	}
 }
 
+func (d *TerminalDisplay) showTopReportCI(packages map[string]*PackageState, results map[string]*TestResult) {
+	report := d.topReport(packages, results)
+	if report.isEmpty() {
+		return
+	}
+
+	fmt.Fprintln(d.writer, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintf(d.writer, "Top %d\n", d.config.Top)
+	fmt.Fprintln(d.writer, strings.Repeat("=", 50))
+
+	showPackage := shouldShowPackageName(packages)
+	for _, section := range report.sections() {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(d.writer, "\n%s\n", section.title)
+		for i, line := range rankedLines(section.entries) {
+			packageInfo := ""
+			if showPackage && section.entries[i].Name != "" {
+				packageInfo = "  " + getShortPackageName(section.entries[i].Package)
+			}
+			fmt.Fprintf(d.writer, "  %s%s\n", line, packageInfo)
+		}
+	}
+}
+
+func (d *TerminalDisplay) topReport(packages map[string]*PackageState, results map[string]*TestResult) topReport {
+	if d.config == nil {
+		return topReport{}
+	}
+	return buildTopReport(packages, results, d.config.Top)
+}
+
+// rankedLines formats ranked entries as aligned "rank. name  duration  share" lines
+func rankedLines(entries []rankedEntry) []string {
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		label := entry.Name
+		switch {
+		case label == "":
+			label = getShortPackageName(entry.Package)
+		case entry.Subtests > 0:
+			label = fmt.Sprintf("%s (%d subtests)", entry.Name, entry.Subtests)
+		}
+		rows[i] = []string{
+			fmt.Sprintf("%2d. %s", i+1, label),
+			formatDuration(entry.Elapsed),
+			fmt.Sprintf("%.1f%%", entry.Share),
+		}
+	}
+	return formatTable(rows)
+}
+
 func (d *TerminalDisplay) displaySlowTestsForPackage(pkgName string, tests []slowTest) {
 	shortPkg := getShortPackageName(pkgName)
 	fmt.Fprintf(d.writer, "\n=== %s%s%s ===\n", colorBlue, shortPkg, colorReset)
 
 	for _, test := range tests {
 		elapsed := formatDuration(test.elapsed)
+		if test.threshold != "" {
+			elapsed += " > " + test.threshold
+		}
 		if test.location != "" {
 			fmt.Fprintf(d.writer, "  %s %s[%s]%s %s(%s)%s\n",
 				test.name, colorBlue, test.location, colorReset, colorRed, elapsed, colorReset)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -234,7 +235,7 @@ func TestTerminalDisplay_ShowFinalResults_Success(t *testing.T) {
 	results := map[string]*TestResult{}
 	startTime := time.Now().Add(-2 * time.Second)
 
-	exitCode := display.ShowFinalResults(packages, results, startTime)
+	exitCode := display.ShowFinalResults(packages, results, nil, startTime)
 
 	if exitCode != 0 {
 		t.Errorf("Expected exit code 0 for successful tests, got %d", exitCode)
@@ -278,7 +279,7 @@ func TestTerminalDisplay_ShowFinalResults_Failure(t *testing.T) {
 	}
 	startTime := time.Now().Add(-2 * time.Second)
 
-	exitCode := display.ShowFinalResults(packages, results, startTime)
+	exitCode := display.ShowFinalResults(packages, results, nil, startTime)
 
 	if exitCode != 1 {
 		t.Errorf("Expected exit code 1 for failed tests, got %d", exitCode)
@@ -571,7 +572,7 @@ func TestTerminalDisplay_CIMode_ShowFinalResults(t *testing.T) {
 	}
 	startTime := time.Now().Add(-2 * time.Second)
 
-	exitCode := display.ShowFinalResults(packages, results, startTime)
+	exitCode := display.ShowFinalResults(packages, results, nil, startTime)
 
 	if exitCode != 1 {
 		t.Errorf("Expected exit code 1 for failed tests, got %d", exitCode)
@@ -611,3 +612,53 @@ func TestTerminalDisplay_CIMode_ShowFinalResults(t *testing.T) {
 		t.Error("CI mode should not contain decorative characters")
 	}
 }
+
+func TestTerminalDisplay_ShowTestOutput(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true, ShowOutput: regexp.MustCompile(`example/TestIntegration`)})
+
+	matched := &TestResult{Package: "example", Test: "TestIntegration/db", Failed: true, Elapsed: 1.5, Output: []string{"    db_test.go:9: connected\n"}}
+	other := &TestResult{Package: "example", Test: "TestUnit"}
+
+	display.ShowTestOutput(matched, "=== RUN   TestIntegration/db\n")
+	display.ShowTestOutput(matched, "    db_test.go:9: connected\n")
+	display.ShowTestOutput(other, "    unit_test.go:3: not shown\n")
+
+	if expected := "TestIntegration/db |     db_test.go:9: connected\n"; buf.String() != expected {
+		t.Errorf("expected only the matching output line with a prefix.\nGot: %q\nExpected: %q", buf.String(), expected)
+	}
+
+	// The failure header is still shown, but the streamed output is not repeated
+	buf.Reset()
+	display.ShowTestResult(matched, false)
+	if !strings.Contains(buf.String(), "FAIL TestIntegration/db") {
+		t.Errorf("expected the failure header, got: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "connected") {
+		t.Errorf("streamed output should not be repeated, got: %q", buf.String())
+	}
+}
+
+func TestTerminalDisplay_ShowAllOutput(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true, ShowAllOutput: true})
+
+	display.ShowTestResult(&TestResult{
+		Package: "example", Test: "TestPass", Passed: true, Elapsed: 0.25,
+		Output: []string{"=== RUN   TestPass\n", "    pass_test.go:5: hello\n", "--- PASS: TestPass (0.25s)\n"},
+	}, true)
+
+	result := buf.String()
+	for _, expected := range []string{"PASS TestPass (0.25s)\n", "        pass_test.go:5: hello\n", "--- PASS: TestPass"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "=== RUN") {
+		t.Error("run markers should not be shown")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"os/exec"
 	"strings"
 	"testing"
@@ -11,6 +12,7 @@ import (
 func TestE2E_HelpFlag(t *testing.T) {
 	t.Parallel()
 	cmd := exec.Command("go", "run", ".", "-help")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		t.Fatalf("Failed to run gotestshow with -help: %v", err)
@@ -37,6 +39,7 @@ func TestE2E_RealTestOutput(t *testing.T) {
 
 	// Run go test -json on example directory and pipe to gotestshow
 	cmd := exec.Command("bash", "-c", "cd example && go test -json ./... | ../gotestshow")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 
 	// The exit code should be non-zero because example has failing tests
@@ -81,6 +84,7 @@ func TestE2E_SuccessfulTests(t *testing.T) {
 
 	// Run go test -json on a successful test (math.go functions that should pass)
 	cmd := exec.Command("bash", "-c", "cd example && go test -json -run TestAdd | ../gotestshow")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 
 	// The exit code should be zero for passing tests
@@ -112,6 +116,7 @@ func TestE2E_BuildFailure(t *testing.T) {
 
 	// Run go test -json on broken package
 	cmd := exec.Command("bash", "-c", "cd example && go test -json ./broken | ../gotestshow")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 
 	// The exit code should be non-zero due to build failure
@@ -148,6 +153,7 @@ func TestE2E_NoStdin(t *testing.T) {
 
 	// Run gotestshow without any input
 	cmd := exec.Command("./gotestshow")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 
 	// Should show help when no stdin
@@ -179,6 +185,7 @@ func TestE2E_Performance(t *testing.T) {
 
 	// Run tests with timeout to ensure it doesn't hang
 	cmd := exec.Command("bash", "-c", "cd example && timeout 30s go test -json ./... | ../gotestshow")
+	cmd.Env = e2eEnv()
 
 	start := time.Now()
 	cmd.CombinedOutput()
@@ -204,6 +211,7 @@ func TestE2E_LongRunningTest(t *testing.T) {
 
 	// Run the slow test - note: some slow tests in example may fail
 	cmd := exec.Command("bash", "-c", "cd example && go test -json -run TestSlowOperation1 | ../gotestshow")
+	cmd.Env = e2eEnv()
 	output, err := cmd.CombinedOutput()
 
 	// Should handle slow tests without issues (this specific test should pass)
@@ -233,9 +241,11 @@ func TestE2E_InvalidJSON(t *testing.T) {
 {"Time":"2023-01-01T00:00:00Z","Action":"run","Package":"example","Test":"TestExample"}
 not json at all
 {"Time":"2023-01-01T00:00:01Z","Action":"pass","Package":"example","Test":"TestExample","Elapsed":0.01}
+{"Time":"2023-01-01T00:00:02Z","Action":"pass","Package":"example","Elapsed":0.02}
 `
 
 	cmd := exec.Command("./gotestshow")
+	cmd.Env = e2eEnv()
 	cmd.Stdin = strings.NewReader(invalidJSON)
 	output, err := cmd.CombinedOutput()
 
@@ -264,9 +274,11 @@ func TestE2E_ColorOutput(t *testing.T) {
 	// Create simple test JSON
 	testJSON := `{"Time":"2023-01-01T00:00:00Z","Action":"run","Package":"example","Test":"TestExample"}
 {"Time":"2023-01-01T00:00:01Z","Action":"pass","Package":"example","Test":"TestExample","Elapsed":0.01}
+{"Time":"2023-01-01T00:00:02Z","Action":"pass","Package":"example","Elapsed":0.02}
 `
 
 	cmd := exec.Command("./gotestshow")
+	cmd.Env = e2eEnv()
 	cmd.Stdin = strings.NewReader(testJSON)
 	output, err := cmd.CombinedOutput()
 
@@ -282,3 +294,16 @@ func TestE2E_ColorOutput(t *testing.T) {
 		t.Error("Output should contain test information")
 	}
 }
+
+// e2eEnv returns the environment for running gotestshow without settings
+// or job summaries leaking in from the environment the tests run in
+func e2eEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "GITHUB_STEP_SUMMARY=") || strings.HasPrefix(kv, envPrefix) {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
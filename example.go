@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxExampleDiffCells bounds the size of the table used to diff the output
+// of an example; larger outputs are shown as go test printed them
+const maxExampleDiffCells = 1 << 22
+
+// exampleFailure is the output of an Example function that printed something
+// other than its // Output: comment
+type exampleFailure struct {
+	Before    []string // Output lines before "got:", such as the --- FAIL line
+	Got       []string
+	Want      []string
+	Unordered bool // The example has an "// Unordered output:" comment
+}
+
+// parseExampleFailure finds the "got:" and "want:" blocks go test prints for
+// a failing example. The blocks hold the output lines as printed, so they are
+// read from the raw output rather than from extractRelevantOutput, which
+// drops empty lines.
+func parseExampleFailure(output []string) (*exampleFailure, bool) {
+	gotIndex := -1
+	for i, line := range output {
+		if strings.HasPrefix(line, "--- FAIL: Example") {
+			gotIndex = -1
+			if i+1 < len(output) && output[i+1] == "got:\n" {
+				gotIndex = i + 1
+			}
+		}
+	}
+	if gotIndex < 0 {
+		return nil, false
+	}
+
+	for i := gotIndex + 1; i < len(output); i++ {
+		if output[i] != "want:\n" && output[i] != "want (unordered):\n" {
+			continue
+		}
+		failure := &exampleFailure{
+			Before:    output[:gotIndex],
+			Got:       exampleLines(output[gotIndex+1 : i]),
+			Want:      exampleLines(output[i+1:]),
+			Unordered: output[i] == "want (unordered):\n",
+		}
+		if failure.Unordered {
+			// go test compares the sorted lines of unordered output
+			sort.Strings(failure.Got)
+			sort.Strings(failure.Want)
+		}
+		return failure, true
+	}
+	return nil, false
+}
+
+// exampleLines strips the newlines of output lines and the empty lines go
+// test prints after a block
+func exampleLines(output []string) []string {
+	lines := make([]string, 0, len(output))
+	for _, line := range output {
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLine is a line of a line diff: ' ' for a common line, '-' for a line
+// only in the first input and '+' for a line only in the second
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// diffLines computes a line diff turning a into b from their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// unifiedDiff formats a line diff as hunks of changes with the given number
+// of common lines around them, each starting with an "@@ -a,b +c,d @@" header
+func unifiedDiff(lines []diffLine, context int) []string {
+	var output []string
+	for start := 0; start < len(lines); {
+		first := start
+		for first < len(lines) && lines[first].Op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// Extend the hunk until a run of common lines too long to keep in it
+		end := first
+		for end < len(lines) {
+			next := end
+			for next < len(lines) && lines[next].Op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				break
+			}
+			for next < len(lines) && lines[next].Op != ' ' {
+				next++
+			}
+			end = next
+		}
+
+		hunkStart := max(first-context, start)
+		hunkEnd := min(end+context, len(lines))
+		output = append(output, hunkHeader(lines, hunkStart, hunkEnd))
+		for _, line := range lines[hunkStart:hunkEnd] {
+			output = append(output, string(line.Op)+line.Text)
+		}
+		start = hunkEnd
+	}
+	return output
+}
+
+// hunkHeader formats the line ranges of lines[start:end] in both inputs
+func hunkHeader(lines []diffLine, start, end int) string {
+	aLine, bLine := 1, 1
+	for _, line := range lines[:start] {
+		if line.Op != '+' {
+			aLine++
+		}
+		if line.Op != '-' {
+			bLine++
+		}
+	}
+	aCount, bCount := 0, 0
+	for _, line := range lines[start:end] {
+		if line.Op != '+' {
+			aCount++
+		}
+		if line.Op != '-' {
+			bCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+}
+
+// hunkRange formats a range of a hunk header like diff -u does
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// exampleDiff returns the unified diff of the expected and actual output of
+// a failing example, or false when the output is too large to diff
+func exampleDiff(failure *exampleFailure) ([]string, bool) {
+	if (len(failure.Want)+1)*(len(failure.Got)+1) > maxExampleDiffCells {
+		return nil, false
+	}
+	return unifiedDiff(diffLines(failure.Want, failure.Got), 3), true
+}
@@ -0,0 +1,25 @@
+package example
+
+import "testing"
+
+func BenchmarkAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Add(i, i)
+	}
+}
+
+func BenchmarkDivide(b *testing.B) {
+	b.Run("small", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Divide(10, 3)
+		}
+	})
+
+	b.Run("large", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Divide(1<<30, 7)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExampleFailure(t *testing.T) {
+	t.Parallel()
+	output := []string{
+		"=== RUN   Example_hello\n",
+		"--- FAIL: Example_hello (0.00s)\n",
+		"got:\n",
+		"hello\n",
+		"\n",
+		"world\n",
+		"want:\n",
+		"hello\n",
+		"\n",
+		"there\n",
+	}
+	failure, ok := parseExampleFailure(output)
+	if !ok {
+		t.Fatal("expected an example failure")
+	}
+	if !reflect.DeepEqual(failure.Got, []string{"hello", "", "world"}) {
+		t.Errorf("unexpected got lines %q", failure.Got)
+	}
+	if !reflect.DeepEqual(failure.Want, []string{"hello", "", "there"}) {
+		t.Errorf("unexpected want lines %q", failure.Want)
+	}
+	if len(failure.Before) != 2 || failure.Unordered {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+
+	unordered, ok := parseExampleFailure([]string{
+		"--- FAIL: Example_sets (0.00s)\n", "got:\n", "b\n", "a\n", "\n", "want (unordered):\n", "a\n", "c\n", "\n",
+	})
+	if !ok || !unordered.Unordered {
+		t.Fatalf("expected an unordered example failure, got %+v", unordered)
+	}
+	if !reflect.DeepEqual(unordered.Got, []string{"a", "b"}) || !reflect.DeepEqual(unordered.Want, []string{"a", "c"}) {
+		t.Errorf("expected sorted lines, got %q and %q", unordered.Got, unordered.Want)
+	}
+
+	for _, output := range [][]string{
+		{"--- FAIL: TestGot (0.00s)\n", "got:\n", "1\n", "want:\n", "2\n"},
+		{"--- FAIL: Example_panic (0.00s)\n", "panic: boom [recovered]\n"},
+	} {
+		if _, ok := parseExampleFailure(output); ok {
+			t.Errorf("did not expect an example failure in %q", output)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		a, b     []string
+		expected []string
+	}{
+		{
+			name:     "identical",
+			a:        []string{"a", "b"},
+			b:        []string{"a", "b"},
+			expected: nil,
+		},
+		{
+			name:     "empty want",
+			a:        nil,
+			b:        []string{"oops"},
+			expected: []string{"@@ -0,0 +1 @@", "+oops"},
+		},
+		{
+			name: "separate hunks",
+			a:    []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"},
+			b:    []string{"one", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+			expected: []string{
+				"@@ -1,4 +1,4 @@", "-1", "+one", " 2", " 3", " 4",
+				"@@ -9,3 +9,4 @@", " 9", " 10", " 11", "+12",
+			},
+		},
+		{
+			name: "close changes share a hunk",
+			a:    []string{"1", "2", "3", "4", "5"},
+			b:    []string{"x", "2", "3", "4", "y"},
+			expected: []string{
+				"@@ -1,5 +1,5 @@", "-1", "+x", " 2", " 3", " 4", "-5", "+y",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := unifiedDiff(diffLines(tt.a, tt.b), 3)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected\n%s\ngot\n%s", strings.Join(tt.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestTerminalDisplay_ExampleDiff(t *testing.T) {
+	t.Parallel()
+	result := &TestResult{
+		Package: "github.com/user/repo",
+		Test:    "Example_hello",
+		Failed:  true,
+		Output: []string{
+			"=== RUN   Example_hello\n",
+			"--- FAIL: Example_hello (0.00s)\n",
+			"got:\n", "hello\n", "world\n",
+			"want:\n", "hello\n", "there\n",
+		},
+	}
+
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true})
+	display.ShowTestResult(result, false)
+	expected := `FAIL Example_hello (0.00s)
+
+        --- FAIL: Example_hello (0.00s)
+        Output differs (-want +got):
+        @@ -1,2 +1,2 @@
+         hello
+        -there
+        +world
+
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	display = NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{})
+	display.ShowTestResult(result, false)
+	for _, expected := range []string{colorRed + "-there" + colorReset, colorGreen + "+world" + colorReset} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%q", expected, buf.String())
+		}
+	}
+}
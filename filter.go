@@ -0,0 +1,100 @@
+package main
+
+import "regexp"
+
+// DisplayFilter selects the packages and tests shown in the live display and
+// summary. Events of other tests are still processed, so totals and the exit
+// code cover the whole run.
+type DisplayFilter struct {
+	Include *regexp.Regexp // Only show tests whose "package/test" matches (nil to show all)
+	Exclude *regexp.Regexp // Hide tests whose "package/test" matches (nil to hide none)
+}
+
+// Shows reports whether a test is shown. Build and package failures are
+// matched as the tests "[BUILD]" and "[PACKAGE]" of their package. A nil
+// filter shows everything.
+func (f *DisplayFilter) Shows(packageName, testName string) bool {
+	if f == nil {
+		return true
+	}
+	name := packageName + "/" + testName
+	if f.Include != nil && !f.Include.MatchString(name) {
+		return false
+	}
+	return f.Exclude == nil || !f.Exclude.MatchString(name)
+}
+
+// filteredRun is the part of a run that passes the display filter
+type filteredRun struct {
+	packages       map[string]*PackageState
+	results        map[string]*TestResult
+	hiddenFailures int // Failed tests, build and package failures that are not shown
+}
+
+// applyDisplayFilter returns copies of packages and results holding only what
+// the filter shows. Package counts no longer include hidden tests, and packages
+// without any shown test are left out unless the package itself matches.
+func applyDisplayFilter(f *DisplayFilter, packages map[string]*PackageState, results map[string]*TestResult) filteredRun {
+	if f == nil {
+		return filteredRun{packages: packages, results: results}
+	}
+
+	run := filteredRun{
+		packages: make(map[string]*PackageState),
+		results:  make(map[string]*TestResult),
+	}
+	copied := make(map[string]*PackageState, len(packages))
+	for name, pkg := range packages {
+		pkgCopy := *pkg
+		copied[name] = &pkgCopy
+		if f.Shows(name, "") {
+			run.packages[name] = &pkgCopy
+		}
+	}
+
+	for key, result := range results {
+		pkg := copied[result.Package]
+		if result.Test == "[PACKAGE]" && pkg != nil && !shouldDisplayPackageFailure(packages[result.Package]) {
+			// Only failing tests caused it; judged on the copy, hiding them
+			// would make it look like a package failure of its own
+			continue
+		}
+		if f.Shows(result.Package, result.Test) {
+			run.results[key] = result
+			if pkg != nil {
+				run.packages[result.Package] = pkg
+			}
+			continue
+		}
+
+		if isSyntheticResult(result) {
+			if result.Failed {
+				run.hiddenFailures++
+			}
+			continue
+		}
+
+		// Mirror how the processor counts tests: every started test adds to
+		// the total, only tests without subtests to the outcome counts
+		if pkg != nil && result.Started {
+			pkg.Total--
+		}
+		if result.HasSubtest {
+			continue
+		}
+		switch {
+		case result.Failed:
+			run.hiddenFailures++
+			if pkg != nil {
+				pkg.Failed--
+				pkg.IndividualTestFailed--
+			}
+		case result.Passed && pkg != nil:
+			pkg.Passed--
+		case result.Skipped && pkg != nil:
+			pkg.Skipped--
+		}
+	}
+
+	return run
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDisplayFilter_Shows(t *testing.T) {
+	t.Parallel()
+	filter := &DisplayFilter{
+		Include: regexp.MustCompile(`example/api/`),
+		Exclude: regexp.MustCompile(`/TestSlow`),
+	}
+
+	tests := []struct {
+		pkg, test string
+		expected  bool
+	}{
+		{"example/api", "TestGet", true},
+		{"example/api", "TestSlowQuery", false},
+		{"example/api", "[BUILD]", true},
+		{"example/db", "TestGet", false},
+	}
+	for _, tt := range tests {
+		if got := filter.Shows(tt.pkg, tt.test); got != tt.expected {
+			t.Errorf("Shows(%q, %q) = %v, expected %v", tt.pkg, tt.test, got, tt.expected)
+		}
+	}
+
+	var none *DisplayFilter
+	if !none.Shows("example", "TestAnything") {
+		t.Error("a nil filter should show everything")
+	}
+}
+
+func filterFixture() (map[string]*PackageState, map[string]*TestResult) {
+	packages := map[string]*PackageState{
+		"example/api": {Name: "example/api", Total: 5, Passed: 2, Failed: 2, IndividualTestFailed: 2, Output: []string{"FAIL\n"}, Completed: true},
+		"example/db":  {Name: "example/db", Total: 1, Failed: 1, IndividualTestFailed: 1, Output: []string{"FAIL\n"}, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/api/TestGet":        {Package: "example/api", Test: "TestGet", Started: true, Passed: true},
+		"example/api/TestPut":        {Package: "example/api", Test: "TestPut", Started: true, Failed: true, HasSubtest: true},
+		"example/api/TestPut/empty":  {Package: "example/api", Test: "TestPut/empty", Started: true, Failed: true},
+		"example/api/TestSlowQuery":  {Package: "example/api", Test: "TestSlowQuery", Started: true, Failed: true},
+		"example/api/[PACKAGE]":      {Package: "example/api", Test: "[PACKAGE]", Failed: true},
+		"example/db/TestConnect":     {Package: "example/db", Test: "TestConnect", Started: true, Failed: true},
+		"example/db/[PACKAGE]":       {Package: "example/db", Test: "[PACKAGE]", Failed: true},
+		"example/broken/[BUILD]":     {Package: "example/broken", Test: "[BUILD]", Failed: true},
+		"example/api/TestPut/filled": {Package: "example/api", Test: "TestPut/filled", Started: true, Passed: true},
+	}
+	return packages, results
+}
+
+func TestApplyDisplayFilter(t *testing.T) {
+	t.Parallel()
+	packages, results := filterFixture()
+	filter := &DisplayFilter{Include: regexp.MustCompile(`example/api/`), Exclude: regexp.MustCompile(`/TestSlow`)}
+
+	run := applyDisplayFilter(filter, packages, results)
+
+	if run.hiddenFailures != 3 {
+		t.Errorf("expected 3 hidden failures (TestSlowQuery, TestConnect and the build failure), got %d", run.hiddenFailures)
+	}
+	if _, exists := run.packages["example/db"]; exists {
+		t.Error("example/db should be filtered out")
+	}
+	api := run.packages["example/api"]
+	if api == nil {
+		t.Fatal("example/api should be shown")
+	}
+	if api.Total != 4 || api.Failed != 1 || api.IndividualTestFailed != 1 || api.Passed != 2 {
+		t.Errorf("expected counts without TestSlowQuery, got total %d, passed %d, failed %d", api.Total, api.Passed, api.Failed)
+	}
+	if packages["example/api"].Failed != 2 {
+		t.Error("the original package state must not be modified")
+	}
+	if _, exists := run.results["example/api/TestSlowQuery"]; exists {
+		t.Error("TestSlowQuery should be hidden")
+	}
+	if _, exists := run.results["example/api/[PACKAGE]"]; exists {
+		t.Error("a package failure caused only by failing tests should not be shown")
+	}
+	if _, exists := run.results["example/api/TestPut/empty"]; !exists {
+		t.Error("TestPut/empty should be shown")
+	}
+
+	if unfiltered := applyDisplayFilter(nil, packages, results); len(unfiltered.results) != len(results) || unfiltered.hiddenFailures != 0 {
+		t.Error("a nil filter should return the run unchanged")
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_HiddenFailures(t *testing.T) {
+	t.Parallel()
+	packages, results := filterFixture()
+
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true, Filter: &DisplayFilter{Include: regexp.MustCompile(`^example/api/TestGet$`)}})
+
+	exitCode := display.ShowFinalResults(packages, results, nil, time.Now())
+
+	output := buf.String()
+	if exitCode != 1 {
+		t.Errorf("hidden failures should still fail the run, got exit code %d", exitCode)
+	}
+	if strings.Contains(output, "Failed Tests Summary") {
+		t.Errorf("no failure should be listed.\nGot:\n%s", output)
+	}
+	for _, expected := range []string{"4 hidden failures", "Total: 6 tests", "Tests failed!"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, output)
+		}
+	}
+}
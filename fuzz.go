@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FuzzProgress is the latest status line of a running fuzz target
+type FuzzProgress struct {
+	Test             string
+	Elapsed          string // As printed by go test, e.g. "3s" or "1m3s"
+	Phase            string // "baseline", "fuzzing" or "minimizing"
+	Baseline         string // Baseline coverage progress, e.g. "3/10"
+	Execs            int64
+	ExecsPerSec      int64
+	NewInteresting   int
+	TotalInteresting int
+}
+
+var (
+	fuzzElapsedRe  = regexp.MustCompile(`^fuzz: elapsed: (\S+?),`)
+	fuzzBaselineRe = regexp.MustCompile(`gathering baseline coverage: (\d+/\d+) completed`)
+	fuzzExecsRe    = regexp.MustCompile(`execs: (\d+) \((\d+)/sec\)`)
+	fuzzNewRe      = regexp.MustCompile(`new interesting: (\d+) \(total: (\d+)\)`)
+	fuzzInputRe    = regexp.MustCompile(`Failing input written to (testdata/fuzz/\S+)`)
+)
+
+// isFuzzStatusLine reports whether an output line is a status line of the fuzzer
+func isFuzzStatusLine(output string) bool {
+	return strings.HasPrefix(output, "fuzz: ")
+}
+
+// updateFuzzProgress returns the progress after a status line of the fuzzer,
+// such as "fuzz: elapsed: 3s, execs: 1234 (411/sec), new interesting: 2 (total: 3)".
+// The previous progress is not modified, as the display may be reading it.
+func updateFuzzProgress(previous *FuzzProgress, test, output string) *FuzzProgress {
+	progress := FuzzProgress{Test: test}
+	if previous != nil {
+		progress = *previous
+	}
+
+	line := strings.TrimSpace(output)
+	if match := fuzzElapsedRe.FindStringSubmatch(line); match != nil {
+		progress.Elapsed = match[1]
+	}
+	switch {
+	case strings.Contains(line, "minimizing"):
+		progress.Phase = "minimizing"
+	case strings.Contains(line, "now fuzzing"):
+		progress.Phase = "fuzzing"
+		progress.Baseline = ""
+	case strings.Contains(line, "gathering baseline coverage"):
+		progress.Phase = "baseline"
+		if match := fuzzBaselineRe.FindStringSubmatch(line); match != nil {
+			progress.Baseline = match[1]
+		}
+	case strings.Contains(line, "execs:"):
+		progress.Phase = "fuzzing"
+	}
+	if match := fuzzExecsRe.FindStringSubmatch(line); match != nil {
+		progress.Execs, _ = strconv.ParseInt(match[1], 10, 64)
+		progress.ExecsPerSec, _ = strconv.ParseInt(match[2], 10, 64)
+	}
+	if match := fuzzNewRe.FindStringSubmatch(line); match != nil {
+		progress.NewInteresting, _ = strconv.Atoi(match[1])
+		progress.TotalInteresting, _ = strconv.Atoi(match[2])
+	}
+	return &progress
+}
+
+// parseFuzzInput extracts the corpus file a failing fuzz target wrote
+func parseFuzzInput(output string) (string, bool) {
+	match := fuzzInputRe.FindStringSubmatch(output)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// fuzzRerunCommand returns the command that reruns a fuzz target with its failing input
+func fuzzRerunCommand(result *TestResult) string {
+	pattern := buildRunPattern(result.Test, []string{path.Base(result.FuzzInput)})
+	return fmt.Sprintf("go test -run %s %s", shellQuote(pattern), result.Package)
+}
+
+// formatFuzzProgress formats the status of a fuzz target for the progress line
+func formatFuzzProgress(progress *FuzzProgress) string {
+	switch progress.Phase {
+	case "baseline":
+		return fmt.Sprintf("Fuzzing %s | gathering baseline coverage %s", progress.Test, progress.Baseline)
+	case "minimizing":
+		return fmt.Sprintf("Fuzzing %s | %s | minimizing failing input", progress.Test, progress.Elapsed)
+	}
+	return fmt.Sprintf("Fuzzing %s | %s | %s execs (%s/sec) | %d new interesting (total %d)",
+		progress.Test, progress.Elapsed, formatCount(progress.Execs), formatCount(progress.ExecsPerSec),
+		progress.NewInteresting, progress.TotalInteresting)
+}
+
+// formatCount formats a number with thousands separators
+func formatCount(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
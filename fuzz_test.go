@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUpdateFuzzProgress(t *testing.T) {
+	t.Parallel()
+	lines := []struct {
+		output   string
+		expected FuzzProgress
+	}{
+		{
+			"fuzz: elapsed: 0s, gathering baseline coverage: 3/10 completed\n",
+			FuzzProgress{Test: "FuzzParse", Elapsed: "0s", Phase: "baseline", Baseline: "3/10"},
+		},
+		{
+			"fuzz: elapsed: 0s, gathering baseline coverage: 10/10 completed, now fuzzing with 8 workers\n",
+			FuzzProgress{Test: "FuzzParse", Elapsed: "0s", Phase: "fuzzing"},
+		},
+		{
+			"fuzz: elapsed: 1m3s, execs: 1234567 (41152/sec), new interesting: 2 (total: 12)\n",
+			FuzzProgress{Test: "FuzzParse", Elapsed: "1m3s", Phase: "fuzzing", Execs: 1234567, ExecsPerSec: 41152, NewInteresting: 2, TotalInteresting: 12},
+		},
+		{
+			"fuzz: elapsed: 1m4s, minimizing\n",
+			FuzzProgress{Test: "FuzzParse", Elapsed: "1m4s", Phase: "minimizing", Execs: 1234567, ExecsPerSec: 41152, NewInteresting: 2, TotalInteresting: 12},
+		},
+	}
+
+	var progress *FuzzProgress
+	for _, line := range lines {
+		previous := progress
+		progress = updateFuzzProgress(progress, "FuzzParse", line.output)
+		if *progress != line.expected {
+			t.Errorf("after %q: expected %+v, got %+v", line.output, line.expected, *progress)
+		}
+		if previous != nil && previous == progress {
+			t.Error("the previous progress must not be modified")
+		}
+	}
+
+	fuzzing := lines[2].expected
+	if got := formatFuzzProgress(&fuzzing); got != "Fuzzing FuzzParse | 1m3s | 1,234,567 execs (41,152/sec) | 2 new interesting (total 12)" {
+		t.Errorf("unexpected progress line %q", got)
+	}
+}
+
+func TestEventProcessor_Fuzz(t *testing.T) {
+	t.Parallel()
+	processor := NewEventProcessor()
+	pkg := "github.com/user/repo/parser"
+	events := []TestEvent{
+		{Action: "run", Package: pkg, Test: "FuzzParse"},
+		{Action: "output", Package: pkg, Test: "FuzzParse", Output: "fuzz: elapsed: 3s, execs: 1200 (400/sec), new interesting: 1 (total: 4)\n"},
+	}
+	for _, event := range events {
+		processor.ProcessEvent(event)
+	}
+	if progress := processor.GetPackages()[pkg].Fuzz; progress == nil || progress.Execs != 1200 {
+		t.Fatalf("expected fuzz progress, got %+v", progress)
+	}
+
+	for _, event := range []TestEvent{
+		{Action: "output", Package: pkg, Test: "FuzzParse", Output: "    parse_test.go:20: panic\n"},
+		{Action: "output", Package: pkg, Test: "FuzzParse", Output: "    Failing input written to testdata/fuzz/FuzzParse/582528ddfad69eb5\n"},
+		{Action: "fail", Package: pkg, Test: "FuzzParse", Elapsed: 3.2},
+	} {
+		processor.ProcessEvent(event)
+	}
+	if progress := processor.GetPackages()[pkg].Fuzz; progress != nil {
+		t.Errorf("fuzz progress should be cleared when the target finishes, got %+v", progress)
+	}
+
+	results := processor.GetResults()
+	result := results[pkg+"/FuzzParse"]
+	if result.FuzzInput != "testdata/fuzz/FuzzParse/582528ddfad69eb5" {
+		t.Errorf("unexpected failing input %q", result.FuzzInput)
+	}
+	expected := "go test -run '^FuzzParse$/^582528ddfad69eb5$' github.com/user/repo/parser"
+	if command := fuzzRerunCommand(result); command != expected {
+		t.Errorf("expected %q, got %q", expected, command)
+	}
+	if commands := buildRerunCommands(results); len(commands) != 1 || commands[0].Command != expected {
+		t.Errorf("expected the rerun summary to use the failing input, got %+v", commands)
+	}
+
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, true)
+	display.SetConfig(&Config{CIMode: true})
+	display.ShowTestResult(result, false)
+	output := buf.String()
+	for _, expected := range []string{"Failing input: testdata/fuzz/FuzzParse/582528ddfad69eb5", "Rerun: " + expected} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q.\nGot:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "fuzz: elapsed") {
+		t.Errorf("fuzzer status lines should not be repeated in the failure output.\nGot:\n%s", output)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	t.Parallel()
+	for n, expected := range map[int64]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -4200: "-4,200"} {
+		if got := formatCount(n); got != expected {
+			t.Errorf("formatCount(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
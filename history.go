@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// historyMaxSamples is the number of recent durations kept per test
+	historyMaxSamples = 20
+	// historyMinSamples is the number of earlier runs needed before a test is compared
+	historyMinSamples = 3
+	// historyMinSlowdown ignores tiny absolute changes of very fast tests
+	historyMinSlowdown = 10 * time.Millisecond
+)
+
+// TestHistory records per-test durations of earlier runs
+type TestHistory struct {
+	Tests map[string][]float64 `json:"tests"` // Elapsed seconds by "package/test", oldest first
+}
+
+// LoadTestHistory reads a history file. A missing file yields an empty history.
+func LoadTestHistory(path string) (*TestHistory, error) {
+	history := &TestHistory{Tests: make(map[string][]float64)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("parsing history file: %w", err)
+	}
+	if history.Tests == nil {
+		history.Tests = make(map[string][]float64)
+	}
+	return history, nil
+}
+
+// Save writes the history file
+func (h *TestHistory) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("encoding history: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing history file: %w", err)
+	}
+	return nil
+}
+
+// Record appends the durations of passing tests, keeping the most recent samples
+func (h *TestHistory) Record(results map[string]*TestResult) {
+	for key, result := range results {
+		if !result.Passed || result.HasSubtest || isSyntheticResult(result) {
+			continue
+		}
+		samples := append(h.Tests[key], result.Elapsed)
+		if len(samples) > historyMaxSamples {
+			samples = samples[len(samples)-historyMaxSamples:]
+		}
+		h.Tests[key] = samples
+	}
+}
+
+// Median returns the median duration of a test and the number of samples
+func (h *TestHistory) Median(key string) (float64, int) {
+	samples := h.Tests[key]
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2, len(sorted)
+	}
+	return sorted[mid], len(sorted)
+}
+
+// historyRegression is a test that took much longer than it used to
+type historyRegression struct {
+	result  *TestResult
+	median  float64
+	samples int
+}
+
+// findHistoryRegressions returns tests whose elapsed time exceeded their
+// historical median by factor, largest slowdown first
+func findHistoryRegressions(history *TestHistory, results map[string]*TestResult, factor float64) []historyRegression {
+	if history == nil {
+		return nil
+	}
+
+	var regressions []historyRegression
+	for key, result := range results {
+		if result.HasSubtest || isSyntheticResult(result) || result.Skipped {
+			continue
+		}
+
+		median, samples := history.Median(key)
+		if samples < historyMinSamples {
+			continue
+		}
+		slowdown := time.Duration((result.Elapsed - median) * float64(time.Second))
+		if slowdown < historyMinSlowdown || result.Elapsed <= median*factor {
+			continue
+		}
+		regressions = append(regressions, historyRegression{result: result, median: median, samples: samples})
+	}
+
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].result.Elapsed-regressions[i].median > regressions[j].result.Elapsed-regressions[j].median
+	})
+	return regressions
+}
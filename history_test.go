@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTestHistory_Median(t *testing.T) {
+	t.Parallel()
+	history := &TestHistory{Tests: map[string][]float64{
+		"example/TestOdd":  {0.3, 0.1, 0.2},
+		"example/TestEven": {0.4, 0.1, 0.2, 0.3},
+	}}
+
+	tests := []struct {
+		key             string
+		expectedMedian  float64
+		expectedSamples int
+	}{
+		{"example/TestOdd", 0.2, 3},
+		{"example/TestEven", 0.25, 4},
+		{"example/TestMissing", 0, 0},
+	}
+
+	for _, tt := range tests {
+		median, samples := history.Median(tt.key)
+		if median != tt.expectedMedian || samples != tt.expectedSamples {
+			t.Errorf("Median(%q) = (%v, %d), want (%v, %d)", tt.key, median, samples, tt.expectedMedian, tt.expectedSamples)
+		}
+	}
+}
+
+func TestTestHistory_Record(t *testing.T) {
+	t.Parallel()
+	history := &TestHistory{Tests: map[string][]float64{}}
+	for i := 0; i < historyMaxSamples+5; i++ {
+		history.Tests["example/TestA"] = append(history.Tests["example/TestA"], float64(i))
+	}
+
+	history.Record(map[string]*TestResult{
+		"example/TestA":      {Package: "example", Test: "TestA", Passed: true, Elapsed: 99},
+		"example/TestFailed": {Package: "example", Test: "TestFailed", Failed: true, Elapsed: 1},
+		"example/TestParent": {Package: "example", Test: "TestParent", Passed: true, HasSubtest: true},
+	})
+
+	samples := history.Tests["example/TestA"]
+	if len(samples) != historyMaxSamples || samples[len(samples)-1] != 99 {
+		t.Errorf("Expected %d samples ending with the new one, got %v", historyMaxSamples, samples)
+	}
+	if _, exists := history.Tests["example/TestFailed"]; exists {
+		t.Error("Failed tests should not be recorded")
+	}
+	if _, exists := history.Tests["example/TestParent"]; exists {
+		t.Error("Parent tests should not be recorded")
+	}
+}
+
+func TestLoadTestHistory(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	history, err := LoadTestHistory(path)
+	if err != nil {
+		t.Fatalf("Missing history file should not be an error: %v", err)
+	}
+	if len(history.Tests) != 0 {
+		t.Error("Missing history file should yield an empty history")
+	}
+
+	history.Tests["example/TestA"] = []float64{0.1, 0.2}
+	if err := history.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadTestHistory(path)
+	if err != nil {
+		t.Fatalf("LoadTestHistory() error = %v", err)
+	}
+	if len(loaded.Tests["example/TestA"]) != 2 {
+		t.Errorf("Unexpected history after round trip: %+v", loaded.Tests)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTestHistory(path); err == nil {
+		t.Error("Expected error for invalid history file")
+	}
+}
+
+func TestFindHistoryRegressions(t *testing.T) {
+	t.Parallel()
+	history := &TestHistory{Tests: map[string][]float64{
+		"example/TestFast":     {0.05, 0.05, 0.05},
+		"example/TestStable":   {0.05, 0.05, 0.05},
+		"example/TestTiny":     {0.001, 0.001, 0.001},
+		"example/TestFewRuns":  {0.05},
+		"example/TestSkipped":  {0.05, 0.05, 0.05},
+		"example/TestVerySlow": {0.1, 0.1, 0.1},
+	}}
+	results := map[string]*TestResult{
+		"example/TestFast":     {Package: "example", Test: "TestFast", Passed: true, Elapsed: 0.15},
+		"example/TestStable":   {Package: "example", Test: "TestStable", Passed: true, Elapsed: 0.06},
+		"example/TestTiny":     {Package: "example", Test: "TestTiny", Passed: true, Elapsed: 0.005},
+		"example/TestFewRuns":  {Package: "example", Test: "TestFewRuns", Passed: true, Elapsed: 1},
+		"example/TestSkipped":  {Package: "example", Test: "TestSkipped", Skipped: true, Elapsed: 1},
+		"example/TestVerySlow": {Package: "example", Test: "TestVerySlow", Passed: true, Elapsed: 1},
+	}
+
+	regressions := findHistoryRegressions(history, results, 2)
+
+	var names []string
+	for _, r := range regressions {
+		names = append(names, r.result.Test)
+	}
+	if strings.Join(names, ",") != "TestVerySlow,TestFast" {
+		t.Errorf("findHistoryRegressions() = %v, want [TestVerySlow TestFast]", names)
+	}
+}
+
+func TestRunner_HistorySkipsTruncatedRuns(t *testing.T) {
+	t.Parallel()
+	run := func(input string) *TestHistory {
+		path := filepath.Join(t.TempDir(), "history.json")
+		history := &TestHistory{Tests: make(map[string][]float64)}
+		runner := NewRunner(NewEventProcessor(), NewMockDisplay(), strings.NewReader(input), &bytes.Buffer{})
+		runner.SetConfig(&Config{History: history, HistoryFile: path})
+		runner.Run()
+		return history
+	}
+
+	complete := run(`{"Action":"run","Package":"example","Test":"TestA"}
+{"Action":"pass","Package":"example","Test":"TestA","Elapsed":0.5}
+{"Action":"pass","Package":"example","Elapsed":0.6}
+`)
+	if len(complete.Tests["example/TestA"]) != 1 {
+		t.Errorf("expected the complete run to be recorded, got %v", complete.Tests)
+	}
+
+	truncated := run(`{"Action":"run","Package":"example","Test":"TestA"}
+{"Action":"pass","Package":"example","Test":"TestA","Elapsed":0.5}
+{"Action":"run","Package":"example","Test":"TestB"}
+`)
+	if len(truncated.Tests) != 0 {
+		t.Errorf("expected a truncated run not to be recorded, got %v", truncated.Tests)
+	}
+}
+
+func TestTerminalDisplay_TimingMode_GotSlower(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, false)
+	display.SetConfig(&Config{
+		TimingMode:    true,
+		Threshold:     500 * time.Millisecond,
+		History:       &TestHistory{Tests: map[string][]float64{"example/TestFast": {0.05, 0.05, 0.05}}},
+		HistoryFactor: 2,
+	})
+
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 1, Passed: 1, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestFast": {Package: "example", Test: "TestFast", Passed: true, Elapsed: 0.2},
+	}
+
+	display.ShowFinalResults(packages, results, nil, time.Now())
+
+	output := buf.String()
+	if !strings.Contains(output, "Got Slower") {
+		t.Errorf("Output should contain the got slower section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "TestFast") || !strings.Contains(output, "50ms") || !strings.Contains(output, "200ms") {
+		t.Errorf("Output should show previous and current duration, got:\n%s", output)
+	}
+	if strings.Contains(output, "Slow Tests (>") {
+		t.Error("Test below the threshold should not be listed as slow")
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+)
+
+// WriteHTMLReport writes a self-contained HTML report of the run to path
+func WriteHTMLReport(path string, report runReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing HTML report: %w", err)
+	}
+	if err := runReportTemplate.Execute(file, report); err != nil {
+		file.Close()
+		return fmt.Errorf("writing HTML report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing HTML report: %w", err)
+	}
+	return nil
+}
+
+var runReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"coverage": formatCoverage,
+	"seconds":  func(elapsed float64) string { return fmt.Sprintf("%.6f", elapsed) },
+	"width":    func(percent float64) template.CSS { return template.CSS(fmt.Sprintf("width: %.1f%%", percent)) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>gotestshow report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; background: #f6f8fa; }
+header { padding: 24px 32px; color: #fff; }
+header.pass { background: #1a7f37; }
+header.fail { background: #cf222e; }
+header h1 { margin: 0 0 8px; font-size: 24px; }
+header .stats span { margin-right: 24px; }
+main { padding: 16px 32px 48px; }
+section { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; margin: 16px 0; padding: 16px; }
+h2 { font-size: 18px; margin: 0 0 12px; }
+table { border-collapse: collapse; width: 100%; font-size: 14px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eaeef2; vertical-align: top; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+th[data-order=asc]::after { content: " ▲"; }
+th[data-order=desc]::after { content: " ▼"; }
+td.num, th.num { text-align: right; white-space: nowrap; }
+.status { font-weight: 600; text-transform: uppercase; font-size: 12px; }
+.status.pass { color: #1a7f37; }
+.status.fail { color: #cf222e; }
+.status.skip, .status.incomplete, .status.running { color: #9a6700; }
+.status.quarantined { color: #8250df; }
+.location { color: #57606a; font-size: 12px; }
+summary { cursor: pointer; }
+pre { background: #fff8f8; border: 1px solid #ffcecb; padding: 8px; overflow-x: auto; font-size: 12px; white-space: pre-wrap; }
+.filters { display: flex; gap: 8px; margin-bottom: 12px; }
+.filters input { flex: 1; padding: 6px; }
+.histogram td.bar { width: 70%; }
+.histogram .bar div { background: #54aeff; height: 14px; border-radius: 2px; }
+</style>
+</head>
+<body>
+<header class="{{if .AllPassed}}pass{{else}}fail{{end}}">
+<h1>{{if .AllPassed}}✓ All tests passed{{else}}✗ Tests failed{{end}}</h1>
+<div class="stats">
+<span>Total: {{.Total}}</span>
+<span>Passed: {{.Passed}}</span>
+<span>Failed: {{.Failed}}</span>
+<span>Skipped: {{.Skipped}}</span>
+<span>Time: {{.Duration}}</span>
+<span>Generated: {{.GeneratedAt}}</span>
+</div>
+</header>
+<main>
+<section>
+<h2>Packages</h2>
+<table class="sortable">
+<thead><tr><th>Package</th><th>Status</th><th class="num" data-type="number">Tests</th><th class="num" data-type="number">Passed</th><th class="num" data-type="number">Failed</th><th class="num" data-type="number">Skipped</th><th class="num" data-type="number">Time</th>{{if .HasCoverage}}<th class="num" data-type="number">Coverage</th>{{end}}</tr></thead>
+<tbody>
+{{$coverage := .HasCoverage}}{{range .Packages}}<tr>
+<td>{{.Name}}</td>
+<td class="status {{.Status}}">{{.Status}}</td>
+<td class="num">{{.Total}}</td>
+<td class="num">{{.Passed}}</td>
+<td class="num">{{.Failed}}</td>
+<td class="num">{{.Skipped}}</td>
+<td class="num" data-value="{{seconds .Elapsed}}">{{duration .Elapsed}}</td>
+{{if $coverage}}<td class="num"{{if .HasCoverage}} data-value="{{.Coverage}}">{{coverage .Coverage}}{{else}} data-value="-1">–{{end}}</td>{{end}}
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+<section>
+<h2>Tests</h2>
+<div class="filters">
+<input id="search" type="search" placeholder="Filter by package or test name">
+<select id="status">
+<option value="">All statuses</option>
+<option value="fail">Failed</option>
+<option value="pass">Passed</option>
+<option value="skip">Skipped</option>
+<option value="incomplete">Incomplete</option>
+<option value="quarantined">Quarantined</option>
+</select>
+</div>
+<table class="sortable filterable">
+<thead><tr><th>Test</th><th>Package</th><th>Status</th><th class="num" data-type="number">Time</th></tr></thead>
+<tbody>
+{{range .Tests}}<tr data-status="{{.Status}}" data-search="{{.Package}} {{.Name}}">
+<td data-value="{{.Name}}">{{if .Output}}<details><summary>{{.Name}}{{if .Location}} <span class="location">{{.Location}}</span>{{end}}{{if .Reason}} <span class="location">({{.Reason}})</span>{{end}}</summary><pre>{{.Output}}</pre></details>{{else}}{{.Name}}{{if .Location}} <span class="location">{{.Location}}</span>{{end}}{{end}}</td>
+<td>{{.Package}}</td>
+<td class="status {{.Status}}">{{.Status}}</td>
+<td class="num" data-value="{{seconds .Elapsed}}">{{duration .Elapsed}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+{{if .SlowTests}}<section>
+<h2>Slow tests</h2>
+<table class="sortable">
+<thead><tr><th>Test</th><th>Package</th><th class="num" data-type="number">Time</th><th class="num">Threshold</th></tr></thead>
+<tbody>
+{{range .SlowTests}}<tr>
+<td>{{.Name}}{{if .Location}} <span class="location">{{.Location}}</span>{{end}}</td>
+<td>{{.Package}}</td>
+<td class="num" data-value="{{seconds .Elapsed}}">{{duration .Elapsed}}</td>
+<td class="num">{{.Threshold}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+{{end}}<section>
+<h2>Duration histogram</h2>
+<table class="histogram">
+<tbody>
+{{range .Histogram}}<tr>
+<td>{{.Label}}</td>
+<td class="num">{{.Count}}</td>
+<td class="bar"><div style="{{width .Percent}}"></div></td>
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+</main>
+<script>
+(function () {
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  var headers = table.querySelectorAll("th");
+  headers.forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var ascending = th.dataset.order !== "asc";
+      headers.forEach(function (h) { delete h.dataset.order; });
+      th.dataset.order = ascending ? "asc" : "desc";
+      var numeric = th.dataset.type === "number";
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].dataset.value || a.cells[column].textContent;
+        var y = b.cells[column].dataset.value || b.cells[column].textContent;
+        var order = numeric ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+        return ascending ? order : -order;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+
+var search = document.getElementById("search");
+var statusFilter = document.getElementById("status");
+function applyFilter() {
+  var query = search.value.toLowerCase();
+  document.querySelectorAll("table.filterable tbody tr").forEach(function (row) {
+    var matches = row.dataset.search.toLowerCase().indexOf(query) !== -1;
+    row.hidden = !matches || (statusFilter.value !== "" && row.dataset.status !== statusFilter.value);
+  });
+}
+search.addEventListener("input", applyFilter);
+statusFilter.addEventListener("change", applyFilter);
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[{"test":"TestFlaky","reason":"TICKET-1"}]}`)
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 2, Failed: 2, IndividualTestFailed: 2, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestXSS":   {Package: "example", Test: "TestXSS", Failed: true, Output: []string{"got <script>alert(1)</script>\n"}},
+		"example/TestFlaky": {Package: "example", Test: "TestFlaky", Failed: true, Output: []string{"    a_test.go:9: flaky\n"}},
+	}
+	path := filepath.Join(t.TempDir(), "report.html")
+
+	if err := WriteHTMLReport(path, buildRunReport(applyQuarantine(q, packages, results, time.Now()), &Config{}, time.Second, 1)); err != nil {
+		t.Fatalf("WriteHTMLReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	for _, expected := range []string{"Tests failed", "TestXSS", "&lt;script&gt;alert(1)&lt;/script&gt;", "<details>",
+		`<td class="status quarantined">quarantined</td>`, "(TICKET-1)", `<option value="quarantined">`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected report to contain %q", expected)
+		}
+	}
+	if strings.Contains(html, "<script>alert(1)") {
+		t.Error("test output should be escaped")
+	}
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("report should not reference external resources")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -323,3 +324,59 @@ func TestIntegration_EmptyOutput(t *testing.T) {
 		t.Error("Output should contain success message when no tests to fail")
 	}
 }
+
+// TestIntegration_TruncatedInput tests that a stream cut off mid-run is reported as incomplete
+func TestIntegration_TruncatedInput(t *testing.T) {
+	t.Parallel()
+	jsonInput := `{"Time":"2023-01-01T00:00:00Z","Action":"start","Package":"example"}
+{"Time":"2023-01-01T00:00:00Z","Action":"run","Package":"example","Test":"TestDone"}
+{"Time":"2023-01-01T00:00:01Z","Action":"pass","Package":"example","Test":"TestDone","Elapsed":0.01}
+{"Time":"2023-01-01T00:00:01Z","Action":"run","Package":"example","Test":"TestHanging"}
+{"Time":"2023-01-01T00:00:01Z","Action":"output","Package":"example","Test":"TestHanging","Output":"=== RUN   TestHanging\n"}
+{"Time":"2023-01-01T00:00:00Z","Action":"start","Package":"other"}
+{"Time":"2023-01-01T00:00:02Z","Action":"run","Package":"other","Test":"TestOther"}
+{"Time":"2023-01-01T00:00:03Z","Action":"pass","Package":"other","Test":"TestOther","Elapsed":0.01}
+{"Time":"2023-01-01T00:00:03Z","Action":"pass","Package":"other","Elapsed":0.02}
+`
+
+	tests := []struct {
+		name             string
+		config           *Config
+		expectedExitCode int
+	}{
+		{"fails by default", &Config{}, 1},
+		{"allowed", &Config{Policy: &Policy{MaxSlowTests: -1}}, 0},
+		{"CI mode", &Config{CIMode: true}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var output bytes.Buffer
+			processor := NewEventProcessor()
+			display := NewTerminalDisplay(&output, false)
+			display.SetConfig(tt.config)
+			runner := NewRunner(processor, display, strings.NewReader(jsonInput), &output)
+			runner.SetConfig(tt.config)
+
+			if exitCode := runner.Run(); exitCode != tt.expectedExitCode {
+				t.Errorf("Expected exit code %d, got %d", tt.expectedExitCode, exitCode)
+			}
+
+			// Strip escape sequences so colored and CI output can be checked alike
+			outputStr := regexp.MustCompile("\033\\[[0-9;?]*[A-Za-z]").ReplaceAllString(output.String(), "")
+			if !strings.Contains(outputStr, "INCOMPLETE example") {
+				t.Errorf("Output should list the incomplete package, got:\n%s", outputStr)
+			}
+			if !strings.Contains(outputStr, "TestHanging (still running)") {
+				t.Errorf("Output should list the test that was still running, got:\n%s", outputStr)
+			}
+			if strings.Contains(outputStr, "INCOMPLETE other") {
+				t.Error("Completed packages should not be listed as incomplete")
+			}
+			if failed := strings.Contains(outputStr, "[truncated]"); failed != (tt.expectedExitCode == 1) {
+				t.Errorf("The truncated policy violation should be listed only when it fails the run, got:\n%s", outputStr)
+			}
+		})
+	}
+}
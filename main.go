@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"time"
 )
 
@@ -30,6 +33,8 @@ type TestResult struct {
 	Started    bool
 	Location   string // File name and line number (e.g., "math_test.go:47")
 	HasSubtest bool   // Whether this test has subtests
+	Incomplete bool   // Whether the input ended while the test was still running
+	FuzzInput  string // Failing input a fuzz target wrote, relative to the package (e.g., "testdata/fuzz/FuzzX/1a2b")
 }
 
 // PackageState tracks the state of tests in a package
@@ -41,8 +46,13 @@ type PackageState struct {
 	Skipped              int
 	Running              int
 	Elapsed              float64
-	Output               []string // Store package-level output
-	IndividualTestFailed int      // Number of individual test failures
+	Output               []string      // Store package-level output
+	IndividualTestFailed int           // Number of individual test failures
+	Completed            bool          // Whether a final pass/fail/skip action was received
+	Incomplete           bool          // Whether the input ended before the package completed
+	Coverage             float64       // Statement coverage in percent reported by go test -cover
+	HasCoverage          bool          // Whether coverage was reported for the package
+	Fuzz                 *FuzzProgress // Status of the fuzz target running in the package (nil if not fuzzing)
 }
 
 const (
@@ -65,31 +75,238 @@ type summaryStats struct {
 
 // Config holds the configuration for gotestshow
 type Config struct {
-	TimingMode bool
-	Threshold  time.Duration
-	CIMode     bool
+	Settings         *Settings // Where each setting came from
+	TimingMode       bool
+	Threshold        time.Duration
+	Thresholds       *ThresholdOverrides // Per-package and per-test thresholds (nil to use Threshold only)
+	CIMode           bool
+	Filter           *DisplayFilter // Packages and tests shown in the display (nil to show all)
+	Sort             SortOrder      // Order of packages and failed tests in the summary (empty for by name)
+	TUI              bool           // Browse the results interactively once the run completes
+	ShowOutput       *regexp.Regexp // Stream the output of tests whose "package/test" matches (nil to disable)
+	ShowAllOutput    bool           // Show every test with its output, like failures
+	Top              int            // Number of entries in the slowest tests report (0 to disable)
+	RerunScript      string         // Path to write rerun commands for failed tests (empty to disable)
+	TraceFile        string         // Path to write a Chrome trace of the run (empty to disable)
+	HTMLReport       string         // Path to write an HTML report of the run (empty to disable)
+	MarkdownFile     string         // Path to write a Markdown summary of the run (empty to disable)
+	StepSummaryFile  string         // Job summary file the Markdown summary is appended to (empty to disable)
+	TAPFile          string         // Path to write TAP output to, "-" for stdout (empty to disable)
+	TAPVersion       int            // TAP version to write (13 or 14)
+	JSONFile         string         // Path to copy the input stream to, "-" for stdout (empty to disable)
+	JSONFileEnriched bool           // Copy only valid events, annotated with locations and test status
+	Quarantine       *Quarantine    // Known-broken tests excluded from the exit code
+	Policy           *Policy        // Additional rules deciding the exit code (nil for defaults)
+	Packages         []string       // Package patterns given after the flags, used by watch mode and -changed
+	Changed          bool           // Run go test for the packages affected by git changes instead of reading stdin
+	ChangedRef       string         // Git ref -changed compares against (empty for the merge-base with main)
+	WatchInterval    time.Duration  // How often watch mode polls for changed files
+	CoverProfile     string         // Path to merge the coverage profiles of go test runs gotestshow launches into (empty to disable)
+
+	History       *TestHistory // Durations of earlier runs (nil to disable)
+	HistoryFile   string       // Path the history is loaded from and saved to
+	HistoryFactor float64      // Factor over the historical median that counts as slower
+
+	BenchBaseline   []*BenchmarkResult // Benchmarks of a previous run to compare against
+	BenchSave       string             // Path to save this run's benchmarks (empty to disable)
+	BenchRegression float64            // Percentage change counted as a regression
 }
 
-func parseConfig() (*Config, error) {
-	help := flag.Bool("help", false, "Show help message")
-	timing := flag.Bool("timing", false, "Enable timing mode to show only slow tests and failures")
-	threshold := flag.String("threshold", "500ms", "Threshold for slow tests (e.g., 1s, 500ms)")
-	ci := flag.Bool("ci", false, "Enable CI mode - no escape sequences, only show failures and summary")
-	flag.Parse()
+func parseConfig(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("gotestshow", flag.ContinueOnError)
+	// Errors are returned and reported by the caller; -help shows the full help
+	flags.SetOutput(io.Discard)
+	help := flags.Bool("help", false, "Show help message")
+	timing := flags.Bool("timing", false, "Enable timing mode to show only slow tests and failures")
+	threshold := flags.String("threshold", "500ms", "Threshold for slow tests (e.g., 1s, 500ms)")
+	thresholdsFile := flags.String("thresholds", "", "JSON file with per-package and per-test slow thresholds")
+	ci := flags.Bool("ci", false, "Enable CI mode - no escape sequences, only show failures and summary")
+	tui := flags.Bool("tui", false, "Browse the results in an interactive full-screen view once the run completes")
+	include := flags.String("include", "", "Only display tests whose package/test matches this regexp")
+	exclude := flags.String("exclude", "", "Don't display tests whose package/test matches this regexp")
+	showOutput := flags.String("show-output", "", "Stream the output of tests whose package/test matches this regexp, or \"all\" to show every test with its output")
+	sortFlag := flags.String("sort", "name", "Order of packages and failed tests in the summary: name, duration (slowest first) or status (failures first)")
+	top := flags.Int("top", 0, "Show the N slowest tests, packages and test trees at the end (0 to disable)")
+	rerunScript := flags.String("rerun-script", "", "Write commands to rerun failed tests to this shell script")
+	traceFile := flags.String("trace", "", "Write a Chrome trace-event file of the run for Perfetto or chrome://tracing")
+	htmlReport := flags.String("html", "", "Write a self-contained HTML report of the run to this file")
+	markdownFile := flags.String("markdown", "", "Write a Markdown summary of the run to this file")
+	tapFile := flags.String("tap", "", "Write the results as TAP to this file (\"-\" for stdout; the display then goes to stderr)")
+	tapVersion := flags.Int("tap-version", 14, "TAP version to write: 14 nests packages as subtests, 13 lists all tests in one plan")
+	jsonFile := flags.String("jsonfile", "", "Copy every received line to this file (\"-\" for stdout; the display then goes to stderr)")
+	jsonFileFormat := flags.String("jsonfile-format", "raw", "Format of -jsonfile: raw copies the input, enriched annotates events with locations, quarantine and flaky status")
+	stepSummary := flags.Bool("step-summary", true, "Append a Markdown summary to $GITHUB_STEP_SUMMARY when it is set")
+	quarantineFile := flags.String("quarantine", "", "JSON file listing known-broken tests that don't fail the build")
+	failOnNoTests := flags.Bool("fail-on-no-tests", false, "Fail if no tests were run")
+	failOnEmptyPackage := flags.Bool("fail-on-empty-package", false, "Fail if any package has no tests")
+	failOnSkip := flags.String("fail-on-skip", "", "Fail if a skipped test name matches this regexp")
+	minCoverage := flags.String("min-coverage", "", "Fail if a package's statement coverage is below this percentage; comma-separated PERCENT or PATTERN=PERCENT entries, first match wins (e.g., 'example/api/.*=90,70')")
+	maxSlow := flags.Int("max-slow", -1, "Fail if more than N tests exceed -threshold (negative to disable)")
+	historyFile := flags.String("history", "", "File recording test durations across runs; timing mode reports tests that got slower")
+	historyFactor := flags.Float64("history-factor", 2, "Factor over the historical median duration that counts as slower")
+	benchBaseline := flags.String("bench-baseline", "", "Compare benchmarks against a file saved with -bench-save or a recorded go test -json stream")
+	benchSave := flags.String("bench-save", "", "Save this run's benchmark results to a file for later comparison")
+	benchRegression := flags.Float64("bench-regression", 10, "Percentage change of a benchmark metric counted as a regression")
+	benchFailOnRegression := flags.Bool("bench-fail-on-regression", false, "Fail if any benchmark regressed against the baseline")
+	failOnTruncated := flags.Bool("fail-on-truncated", true, "Fail if the input ended before a package reported its result")
+	allowIncomplete := flags.Bool("allow-incomplete", false, "Don't fail if the input ended before every package reported its result (same as -fail-on-truncated=false)")
+	var changed changedFlag
+	flags.Var(&changed, "changed", "Run go test for the packages affected by changes since a git ref (default: the merge-base with main), e.g. -changed=origin/release")
+	coverProfile := flags.String("coverprofile", "", "With -changed or watch, pass -coverprofile to go test and merge the profiles into this file")
+	watchInterval := flags.Duration("watch-interval", 500*time.Millisecond, "How often watch mode polls the module's .go files for changes")
+	configFile := flags.String("config", "", "Configuration file (default: .gotestshow.{yaml,toml,json} found from the current directory upwards)")
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return nil, fmt.Errorf("help requested")
+	} else if err != nil {
+		return nil, fmt.Errorf("%w (see gotestshow -help)", err)
+	}
 
 	if *help {
 		return nil, fmt.Errorf("help requested")
 	}
 
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+	settings, err := resolveSettings(flags, *configFile, dir, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
 	thresholdDuration, err := time.ParseDuration(*threshold)
 	if err != nil {
 		return nil, fmt.Errorf("invalid threshold format: %w", err)
 	}
 
+	if *watchInterval <= 0 {
+		return nil, fmt.Errorf("invalid -watch-interval %s: must be positive", *watchInterval)
+	}
+
+	sortOrder, err := parseSortOrder(*sortFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	if *tapVersion != 13 && *tapVersion != 14 {
+		return nil, fmt.Errorf("invalid -tap-version %d: must be 13 or 14", *tapVersion)
+	}
+
+	if *jsonFileFormat != "raw" && *jsonFileFormat != "enriched" {
+		return nil, fmt.Errorf("invalid -jsonfile-format %q: must be raw or enriched", *jsonFileFormat)
+	}
+	if *jsonFile == "-" && *tapFile == "-" {
+		return nil, fmt.Errorf("-jsonfile and -tap cannot both write to stdout")
+	}
+
+	var filter *DisplayFilter
+	if *include != "" || *exclude != "" {
+		filter = &DisplayFilter{}
+		if *include != "" {
+			if filter.Include, err = regexp.Compile(*include); err != nil {
+				return nil, fmt.Errorf("invalid -include pattern: %w", err)
+			}
+		}
+		if *exclude != "" {
+			if filter.Exclude, err = regexp.Compile(*exclude); err != nil {
+				return nil, fmt.Errorf("invalid -exclude pattern: %w", err)
+			}
+		}
+	}
+
+	var showOutputPattern *regexp.Regexp
+	if *showOutput != "" && *showOutput != "all" {
+		if showOutputPattern, err = regexp.Compile(*showOutput); err != nil {
+			return nil, fmt.Errorf("invalid -show-output pattern: %w", err)
+		}
+	}
+
+	var stepSummaryFile string
+	if *stepSummary {
+		stepSummaryFile = os.Getenv("GITHUB_STEP_SUMMARY")
+	}
+
+	var thresholds *ThresholdOverrides
+	if *thresholdsFile != "" {
+		if thresholds, err = LoadThresholdOverrides(*thresholdsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var quarantine *Quarantine
+	if *quarantineFile != "" {
+		if quarantine, err = LoadQuarantine(*quarantineFile); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := DefaultPolicy()
+	policy.FailOnNoTests = *failOnNoTests
+	policy.FailOnEmptyPackage = *failOnEmptyPackage
+	policy.MaxSlowTests = *maxSlow
+	policy.FailOnTruncated = *failOnTruncated && !*allowIncomplete
+	policy.FailOnBenchRegression = *benchFailOnRegression
+	if *minCoverage != "" {
+		if policy.MinCoverage, err = parseCoverageRules(*minCoverage); err != nil {
+			return nil, err
+		}
+	}
+	if *failOnSkip != "" {
+		if policy.FailOnSkip, err = regexp.Compile(*failOnSkip); err != nil {
+			return nil, fmt.Errorf("invalid -fail-on-skip pattern: %w", err)
+		}
+	}
+
+	var baseline []*BenchmarkResult
+	if *benchBaseline != "" {
+		if baseline, err = LoadBenchmarkBaseline(*benchBaseline); err != nil {
+			return nil, err
+		}
+	}
+
+	var history *TestHistory
+	if *historyFile != "" {
+		if history, err = LoadTestHistory(*historyFile); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Config{
-		TimingMode: *timing,
-		Threshold:  thresholdDuration,
-		CIMode:     *ci,
+		Settings:         settings,
+		TimingMode:       *timing,
+		Threshold:        thresholdDuration,
+		Thresholds:       thresholds,
+		CIMode:           *ci,
+		Filter:           filter,
+		Sort:             sortOrder,
+		TUI:              *tui,
+		ShowOutput:       showOutputPattern,
+		ShowAllOutput:    *showOutput == "all",
+		Top:              *top,
+		RerunScript:      *rerunScript,
+		TraceFile:        *traceFile,
+		HTMLReport:       *htmlReport,
+		MarkdownFile:     *markdownFile,
+		StepSummaryFile:  stepSummaryFile,
+		TAPFile:          *tapFile,
+		TAPVersion:       *tapVersion,
+		JSONFile:         *jsonFile,
+		JSONFileEnriched: *jsonFileFormat == "enriched",
+		Quarantine:       quarantine,
+		Policy:           &policy,
+		Packages:         flags.Args(),
+		Changed:          changed.enabled,
+		ChangedRef:       changed.ref,
+		WatchInterval:    *watchInterval,
+		CoverProfile:     *coverProfile,
+
+		History:       history,
+		HistoryFile:   *historyFile,
+		HistoryFactor: *historyFactor,
+
+		BenchBaseline:   baseline,
+		BenchSave:       *benchSave,
+		BenchRegression: *benchRegression,
 	}, nil
 }
 
@@ -99,7 +316,23 @@ func hasStdinInput() bool {
 }
 
 func main() {
-	config, err := parseConfig()
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiffCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "coverdiff" {
+		os.Exit(runCoverDiffCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatchCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	config, err := parseConfig(os.Args[1:])
 	if err != nil {
 		if err.Error() == "help requested" {
 			display := NewTerminalDisplay(os.Stdout, true)
@@ -110,7 +343,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	display := NewTerminalDisplay(os.Stdout, true)
+	// Keep stdout clean for consumers of the TAP or JSON stream
+	output := os.Stdout
+	if config.TAPFile == "-" || config.JSONFile == "-" {
+		output = os.Stderr
+	}
+
+	if config.Changed {
+		os.Exit(runChanged(config, output, os.Stderr))
+	}
+
+	display := NewTerminalDisplay(output, true)
 	display.SetConfig(config)
 
 	if !hasStdinInput() {
@@ -119,7 +362,7 @@ func main() {
 	}
 
 	processor := NewEventProcessor()
-	runner := NewRunner(processor, display, os.Stdin, os.Stdout)
+	runner := NewRunner(processor, display, os.Stdin, output)
 	runner.SetConfig(config)
 	os.Exit(runner.Run())
 }
@@ -1,10 +1,29 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
 
+func TestParseConfig_FlagErrors(t *testing.T) {
+	t.Parallel()
+	for _, args := range [][]string{{"-no-such-flag"}, {"-top", "many"}, {"-threshold"}} {
+		_, err := parseConfig(args)
+		if err == nil {
+			t.Errorf("parseConfig(%q): expected an error", args)
+			continue
+		}
+		if !strings.Contains(err.Error(), "-help") {
+			t.Errorf("parseConfig(%q): error should point to -help, got %v", args, err)
+		}
+	}
+
+	if _, err := parseConfig([]string{"-h"}); err == nil || err.Error() != "help requested" {
+		t.Errorf("parseConfig(-h): expected the help to be requested, got %v", err)
+	}
+}
+
 func TestConfigParsing(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
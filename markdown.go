@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+)
+
+const (
+	// markdownMaxBytes keeps the summary below the size limit of pull request comments
+	markdownMaxBytes = 60000
+	// markdownMaxOutputLines is the number of output lines shown per failed test
+	markdownMaxOutputLines = 30
+	// markdownMaxLineLength is the number of characters shown per output line
+	markdownMaxLineLength = 500
+	// markdownSlowestTests is the number of tests in the slowest tests table
+	markdownSlowestTests = 10
+)
+
+// renderMarkdown renders a compact Markdown summary of the run for pull
+// request comments and job summaries
+func renderMarkdown(report runReport, slowest []rankedEntry) string {
+	var b strings.Builder
+
+	if report.AllPassed {
+		b.WriteString("## ✅ All tests passed\n\n")
+	} else {
+		b.WriteString("## ❌ Tests failed\n\n")
+	}
+	b.WriteString("| Total | Passed | Failed | Skipped | Time |\n")
+	b.WriteString("|------:|-------:|-------:|--------:|-----:|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %s |\n", report.Total, report.Passed, report.Failed, report.Skipped, report.Duration)
+
+	slowSection := renderMarkdownSlowest(slowest)
+
+	var failed, quarantined []reportTest
+	for _, test := range report.Tests {
+		switch test.Status {
+		case "fail":
+			failed = append(failed, test)
+		case "quarantined":
+			quarantined = append(quarantined, test)
+		}
+	}
+	renderMarkdownFailures(&b, "Failed tests", "failed", failed, len(slowSection))
+	renderMarkdownFailures(&b, "Quarantined tests", "quarantined", quarantined, len(slowSection))
+
+	b.WriteString(slowSection)
+	return b.String()
+}
+
+// renderMarkdownFailures renders a section of failure blocks, leaving out the
+// blocks that no longer fit the size limit once reserved bytes are added
+func renderMarkdownFailures(b *strings.Builder, title, kind string, tests []reportTest, reserved int) {
+	if len(tests) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for i, test := range tests {
+		block := renderMarkdownFailure(test)
+		if b.Len()+len(block)+reserved > markdownMaxBytes {
+			fmt.Fprintf(b, "_…and %d more %s tests not shown._\n\n", len(tests)-i, kind)
+			return
+		}
+		b.WriteString(block)
+	}
+}
+
+// renderMarkdownFailure renders a collapsible block with the output of a failed test
+func renderMarkdownFailure(test reportTest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<details>\n<summary><code>%s</code> in <code>%s</code>", html.EscapeString(test.Name), html.EscapeString(test.Package))
+	if test.Location != "" {
+		fmt.Fprintf(&b, " (<code>%s</code>)", html.EscapeString(test.Location))
+	}
+	if test.Reason != "" {
+		fmt.Fprintf(&b, ": %s", html.EscapeString(test.Reason))
+	}
+	b.WriteString("</summary>\n\n")
+
+	output := strings.TrimRight(test.Output, "\n")
+	if output != "" {
+		lines := strings.Split(output, "\n")
+		if len(lines) > markdownMaxOutputLines {
+			omitted := len(lines) - markdownMaxOutputLines
+			lines = append(lines[:markdownMaxOutputLines], fmt.Sprintf("… %d more lines", omitted))
+		}
+		for i, line := range lines {
+			if runes := []rune(line); len(runes) > markdownMaxLineLength {
+				lines[i] = string(runes[:markdownMaxLineLength]) + "…"
+			}
+		}
+		fence := codeFence(output)
+		fmt.Fprintf(&b, "%s\n%s\n%s\n\n", fence, strings.Join(lines, "\n"), fence)
+	}
+	b.WriteString("</details>\n\n")
+	return b.String()
+}
+
+func renderMarkdownSlowest(slowest []rankedEntry) string {
+	if len(slowest) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### Slowest tests\n\n")
+	b.WriteString("| Test | Package | Time |\n")
+	b.WriteString("|------|---------|-----:|\n")
+	for _, entry := range slowest {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCode(entry.Name), markdownCode(entry.Package), formatDuration(entry.Elapsed))
+	}
+	return b.String()
+}
+
+// codeFence returns a backtick fence longer than any backtick run in content
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// markdownCode formats text as inline code that is safe inside a table cell
+func markdownCode(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+	return "`" + text + "`"
+}
+
+// WriteMarkdownSummary writes the summary to path, appending to an existing
+// file if requested (as GitHub Actions expects for GITHUB_STEP_SUMMARY)
+func WriteMarkdownSummary(path, summary string, appendToFile bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendToFile {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing Markdown summary: %w", err)
+	}
+	if _, err := file.WriteString(summary); err != nil {
+		file.Close()
+		return fmt.Errorf("writing Markdown summary: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing Markdown summary: %w", err)
+	}
+	return nil
+}
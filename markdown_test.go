@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	report := runReport{
+		Duration: "1.500s",
+		Total:    3,
+		Passed:   1,
+		Failed:   1,
+		Skipped:  1,
+		Tests: []reportTest{
+			{Package: "example", Name: "TestFail<T>", Status: "fail", Location: "a_test.go:7", Output: "    a_test.go:7: got ```x```\n"},
+			{Package: "example", Name: "TestPass", Status: "pass"},
+			{Package: "example", Name: "TestFlaky", Status: "quarantined", Reason: "TICKET-1", Output: "    a_test.go:9: flaky\n"},
+		},
+	}
+	slowest := []rankedEntry{{Package: "example", Name: "TestSlow|pipe", Elapsed: 1.2}}
+
+	markdown := renderMarkdown(report, slowest)
+
+	for _, expected := range []string{
+		"## ❌ Tests failed",
+		"| 3 | 1 | 1 | 1 | 1.500s |",
+		"<summary><code>TestFail&lt;T&gt;</code> in <code>example</code> (<code>a_test.go:7</code>)</summary>",
+		"````\n    a_test.go:7: got ```x```\n````",
+		"### Quarantined tests\n\n<details>\n<summary><code>TestFlaky</code> in <code>example</code>: TICKET-1</summary>",
+		"### Slowest tests",
+		"| `TestSlow\\|pipe` | `example` | 1.200s |",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("expected Markdown to contain %q.\nGot:\n%s", expected, markdown)
+		}
+	}
+	if strings.Contains(markdown, "TestPass") {
+		t.Error("passing tests should not be listed")
+	}
+
+	report.AllPassed = true
+	if passed := renderMarkdown(report, nil); !strings.Contains(passed, "## ✅ All tests passed") {
+		t.Errorf("expected passing header.\nGot:\n%s", passed)
+	}
+}
+
+func TestRenderMarkdown_Truncation(t *testing.T) {
+	t.Parallel()
+	longOutput := strings.Repeat("x", markdownMaxLineLength+10) + "\n" + strings.Repeat("line\n", markdownMaxOutputLines+5)
+
+	var report runReport
+	for i := 0; i < 200; i++ {
+		report.Tests = append(report.Tests, reportTest{Package: "example", Name: "TestFail", Status: "fail", Output: longOutput})
+	}
+
+	markdown := renderMarkdown(report, []rankedEntry{{Package: "example", Name: "TestSlow", Elapsed: 1}})
+
+	if len(markdown) > markdownMaxBytes {
+		t.Errorf("Markdown is %d bytes, want at most %d", len(markdown), markdownMaxBytes)
+	}
+	if !strings.Contains(markdown, "more failed tests not shown") {
+		t.Error("expected a note about omitted failures")
+	}
+	if !strings.Contains(markdown, "… 6 more lines") {
+		t.Error("expected long output to be cut off")
+	}
+	if strings.Contains(markdown, strings.Repeat("x", markdownMaxLineLength+1)) {
+		t.Error("expected long lines to be cut off")
+	}
+	if !strings.Contains(markdown, "`TestSlow`") {
+		t.Error("slowest tests should be kept when failures are truncated")
+	}
+}
+
+func TestWriteMarkdownSummary(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "summary.md")
+
+	for _, summary := range []string{"first\n", "second\n"} {
+		if err := WriteMarkdownSummary(path, summary, true); err != nil {
+			t.Fatalf("WriteMarkdownSummary() error = %v", err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first\nsecond\n" {
+		t.Errorf("appended summary = %q", data)
+	}
+
+	if err := WriteMarkdownSummary(path, "third\n", false); err != nil {
+		t.Fatalf("WriteMarkdownSummary() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "third\n" {
+		t.Errorf("overwritten summary = %q", data)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Policy holds the rules deciding whether a run fails beyond plain test failures
+type Policy struct {
+	FailOnNoTests         bool           // Fail if no tests ran at all
+	FailOnEmptyPackage    bool           // Fail if any package has no tests
+	FailOnSkip            *regexp.Regexp // Fail if a skipped test name matches (nil to disable)
+	MaxSlowTests          int            // Fail if more tests than this exceed the threshold (negative to disable)
+	MinCoverage           []CoverageRule // Fail if a package's coverage is below the first matching minimum
+	FailOnBenchRegression bool           // Fail if any benchmark metric regressed against the baseline
+	FailOnTruncated       bool           // Fail if the input ended before a package reported its final result
+}
+
+// DefaultPolicy returns a policy that fails on test failures and on input
+// that ended before every package finished
+func DefaultPolicy() Policy {
+	return Policy{MaxSlowTests: -1, FailOnTruncated: true}
+}
+
+// policyViolation describes a rule that was violated by the run
+type policyViolation struct {
+	Rule   string
+	Detail string
+}
+
+// Evaluate checks the run against every enabled rule and returns the
+// violations. deltas is the comparison of the benchmarks against the
+// baseline, if any.
+func (p Policy) Evaluate(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta, threshold time.Duration, overrides *ThresholdOverrides) []policyViolation {
+	var violations []policyViolation
+
+	if p.FailOnNoTests {
+		total := 0
+		for _, pkg := range packages {
+			total += pkg.Total
+		}
+		if total == 0 {
+			violations = append(violations, policyViolation{
+				Rule:   "no-tests",
+				Detail: "no tests were run",
+			})
+		}
+	}
+
+	if p.FailOnEmptyPackage {
+		for _, name := range sortedPackageNames(packages) {
+			if packages[name].Total == 0 && !hasSyntheticFailure(name, results) {
+				violations = append(violations, policyViolation{
+					Rule:   "empty-package",
+					Detail: fmt.Sprintf("%s has no tests", getShortPackageName(name)),
+				})
+			}
+		}
+	}
+
+	if p.FailOnSkip != nil {
+		for _, result := range sortedResults(results) {
+			if result.Skipped && !result.HasSubtest && p.FailOnSkip.MatchString(result.Test) {
+				violations = append(violations, policyViolation{
+					Rule:   "skip",
+					Detail: fmt.Sprintf("%s was skipped in %s", result.Test, getShortPackageName(result.Package)),
+				})
+			}
+		}
+	}
+
+	if p.MaxSlowTests >= 0 {
+		slow := 0
+		for _, result := range results {
+			if result.HasSubtest || isSyntheticResult(result) {
+				continue
+			}
+			testThreshold, _ := thresholdFor(overrides, threshold, result.Package, result.Test)
+			if exceedsThreshold(result.Elapsed, testThreshold) {
+				slow++
+			}
+		}
+		if slow > p.MaxSlowTests {
+			limit := threshold.String()
+			if overrides != nil && len(overrides.Entries) > 0 {
+				limit = "their threshold"
+			}
+			violations = append(violations, policyViolation{
+				Rule:   "max-slow",
+				Detail: fmt.Sprintf("%d tests slower than %s (max %d)", slow, limit, p.MaxSlowTests),
+			})
+		}
+	}
+
+	if len(p.MinCoverage) > 0 {
+		reported := false
+		for _, name := range sortedPackageNames(packages) {
+			pkg := packages[name]
+			if !pkg.HasCoverage {
+				continue
+			}
+			reported = true
+			if minimum, ok := coverageMinimum(p.MinCoverage, name); ok && pkg.Coverage < minimum {
+				violations = append(violations, policyViolation{
+					Rule:   "min-coverage",
+					Detail: fmt.Sprintf("%s coverage %s is below %s", getShortPackageName(name), formatCoverage(pkg.Coverage), formatCoverage(minimum)),
+				})
+			}
+		}
+		if !reported {
+			violations = append(violations, policyViolation{
+				Rule:   "min-coverage",
+				Detail: "no coverage was reported (run go test with -cover)",
+			})
+		}
+	}
+
+	if p.FailOnTruncated {
+		for _, name := range sortedPackageNames(packages) {
+			if packages[name].Incomplete {
+				violations = append(violations, policyViolation{
+					Rule:   "truncated",
+					Detail: fmt.Sprintf("%s never reported a final result (truncated input?)", getShortPackageName(name)),
+				})
+			}
+		}
+	}
+
+	if p.FailOnBenchRegression {
+		if regressions := countRegressions(deltas); regressions > 0 {
+			violations = append(violations, policyViolation{
+				Rule:   "bench-regression",
+				Detail: fmt.Sprintf("%d benchmark metrics regressed against the baseline", regressions),
+			})
+		}
+	}
+
+	return violations
+}
+
+// hasSyntheticFailure reports whether the package failed to build or failed outside of tests
+func hasSyntheticFailure(packageName string, results map[string]*TestResult) bool {
+	for _, suffix := range []string{"[BUILD]", "[PACKAGE]"} {
+		if result, exists := results[fmt.Sprintf("%s/%s", packageName, suffix)]; exists && result.Failed {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedPackageNames(packages map[string]*PackageState) []string {
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedResults(results map[string]*TestResult) []*TestResult {
+	sorted := make([]*TestResult, 0, len(results))
+	for _, result := range results {
+		sorted = append(sorted, result)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Package != sorted[j].Package {
+			return sorted[i].Package < sorted[j].Package
+		}
+		return sorted[i].Test < sorted[j].Test
+	})
+	return sorted
+}
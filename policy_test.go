@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 3, Passed: 2, Skipped: 1, Completed: true, Coverage: 62.5, HasCoverage: true},
+		"empty":   {Name: "empty", Completed: true},
+		"cut":     {Name: "cut", Total: 1, Running: 1, Incomplete: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestFast":     {Package: "example", Test: "TestFast", Passed: true, Elapsed: 0.1},
+		"example/TestSlow":     {Package: "example", Test: "TestSlow", Passed: true, Elapsed: 2.0},
+		"example/TestDatabase": {Package: "example", Test: "TestDatabase", Skipped: true},
+		"cut/TestRunning":      {Package: "cut", Test: "TestRunning", Started: true, Incomplete: true},
+	}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		expected []string
+	}{
+		{
+			name:     "default policy",
+			policy:   DefaultPolicy(),
+			expected: []string{"truncated"},
+		},
+		{
+			name:     "truncated input",
+			policy:   Policy{MaxSlowTests: -1, FailOnTruncated: true},
+			expected: []string{"truncated"},
+		},
+		{
+			name:     "truncated input allowed",
+			policy:   Policy{MaxSlowTests: -1},
+			expected: nil,
+		},
+		{
+			name:     "empty package",
+			policy:   Policy{MaxSlowTests: -1, FailOnEmptyPackage: true},
+			expected: []string{"empty-package"},
+		},
+		{
+			name:     "skip pattern matches",
+			policy:   Policy{MaxSlowTests: -1, FailOnSkip: regexp.MustCompile("Database")},
+			expected: []string{"skip"},
+		},
+		{
+			name:     "skip pattern does not match",
+			policy:   Policy{MaxSlowTests: -1, FailOnSkip: regexp.MustCompile("Network")},
+			expected: nil,
+		},
+		{
+			name:     "too many slow tests",
+			policy:   Policy{MaxSlowTests: 0},
+			expected: []string{"max-slow"},
+		},
+		{
+			name:     "slow tests within limit",
+			policy:   Policy{MaxSlowTests: 1},
+			expected: nil,
+		},
+		{
+			name:     "coverage below the matching minimum",
+			policy:   Policy{MaxSlowTests: -1, MinCoverage: []CoverageRule{{Package: regexp.MustCompile("^example$"), Min: 70}, {Min: 10}}},
+			expected: []string{"min-coverage"},
+		},
+		{
+			name:     "coverage above the minimum",
+			policy:   Policy{MaxSlowTests: -1, MinCoverage: []CoverageRule{{Min: 60}}},
+			expected: nil,
+		},
+		{
+			name:     "no rule matches",
+			policy:   Policy{MaxSlowTests: -1, MinCoverage: []CoverageRule{{Package: regexp.MustCompile("^other$"), Min: 90}}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			violations := tt.policy.Evaluate(packages, results, nil, 500*time.Millisecond, nil)
+
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Evaluate() rules = %v, want %v", rules, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolicy_Evaluate_NoTests(t *testing.T) {
+	t.Parallel()
+	policy := Policy{MaxSlowTests: -1, FailOnNoTests: true}
+
+	violations := policy.Evaluate(map[string]*PackageState{}, map[string]*TestResult{}, nil, 0, nil)
+	if len(violations) != 1 || violations[0].Rule != "no-tests" {
+		t.Errorf("Expected no-tests violation, got %+v", violations)
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_PolicyViolation(t *testing.T) {
+	t.Parallel()
+	for _, ci := range []bool{false, true} {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, false)
+		display.SetConfig(&Config{
+			CIMode: ci,
+			Policy: &Policy{MaxSlowTests: -1, FailOnEmptyPackage: true},
+		})
+
+		packages := map[string]*PackageState{
+			"example": {Name: "example", Total: 1, Passed: 1, Completed: true},
+			"empty":   {Name: "empty", Completed: true},
+		}
+		results := map[string]*TestResult{
+			"example/TestOK": {Package: "example", Test: "TestOK", Passed: true},
+		}
+
+		exitCode := display.ShowFinalResults(packages, results, nil, time.Now())
+
+		output := buf.String()
+		if exitCode != 1 {
+			t.Errorf("CI=%v: expected exit code 1 for policy violation, got %d", ci, exitCode)
+		}
+		if !strings.Contains(output, "Policy violations") || !strings.Contains(output, "empty has no tests") {
+			t.Errorf("CI=%v: output should list the violation, got:\n%s", ci, output)
+		}
+		if !strings.Contains(output, "Tests failed") {
+			t.Errorf("CI=%v: output should report failure, got:\n%s", ci, output)
+		}
+	}
+}
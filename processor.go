@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 )
@@ -11,13 +14,17 @@ type EventProcessor interface {
 	ProcessEvent(event TestEvent)
 	GetResults() map[string]*TestResult
 	GetPackages() map[string]*PackageState
+	GetBenchmarks() []*BenchmarkResult
 	HasTestsStarted() bool
+	Finalize()
 }
 
 // DefaultEventProcessor is the default implementation of EventProcessor
 type DefaultEventProcessor struct {
 	results         map[string]*TestResult
 	packages        map[string]*PackageState
+	benchmarks      []*BenchmarkResult
+	partialLines    map[string]string // Output not yet terminated by a newline, by result key or package
 	mu              sync.RWMutex
 	hasTestsStarted bool
 }
@@ -25,8 +32,9 @@ type DefaultEventProcessor struct {
 // NewEventProcessor creates a new EventProcessor
 func NewEventProcessor() EventProcessor {
 	return &DefaultEventProcessor{
-		results:  make(map[string]*TestResult),
-		packages: make(map[string]*PackageState),
+		results:      make(map[string]*TestResult),
+		packages:     make(map[string]*PackageState),
+		partialLines: make(map[string]string),
 	}
 }
 
@@ -80,6 +88,16 @@ func (p *DefaultEventProcessor) GetPackages() map[string]*PackageState {
 	return packages
 }
 
+// GetBenchmarks returns the benchmark results in the order they were reported
+func (p *DefaultEventProcessor) GetBenchmarks() []*BenchmarkResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	benchmarks := make([]*BenchmarkResult, len(p.benchmarks))
+	copy(benchmarks, p.benchmarks)
+	return benchmarks
+}
+
 // HasTestsStarted returns whether any tests have started
 func (p *DefaultEventProcessor) HasTestsStarted() bool {
 	p.mu.RLock()
@@ -87,6 +105,44 @@ func (p *DefaultEventProcessor) HasTestsStarted() bool {
 	return p.hasTestsStarted
 }
 
+// Finalize marks packages and tests that never completed as incomplete.
+// It is called once the input has ended.
+func (p *DefaultEventProcessor) Finalize() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, pkg := range p.packages {
+		if !pkg.Completed {
+			pkg.Incomplete = true
+		}
+	}
+	for _, result := range p.results {
+		if result.Started && !result.Passed && !result.Failed && !result.Skipped {
+			result.Incomplete = true
+		}
+	}
+}
+
+// processRecordedRun feeds a recorded `go test -json` stream through a new
+// event processor and finalizes it. Lines that are not events are skipped.
+func processRecordedRun(r io.Reader) (EventProcessor, error) {
+	processor := NewEventProcessor()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event TestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		processor.ProcessEvent(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	processor.Finalize()
+	return processor, nil
+}
+
 func (p *DefaultEventProcessor) processTestEvent(event TestEvent) {
 	key := fmt.Sprintf("%s/%s", event.Package, event.Test)
 	result := p.ensureTestResult(key, event)
@@ -99,11 +155,65 @@ func (p *DefaultEventProcessor) processTestEvent(event TestEvent) {
 		p.handleTestRun(result, pkg)
 	case "output":
 		p.handleTestOutput(result, event)
+		if isFuzzStatusLine(event.Output) {
+			pkg.Fuzz = updateFuzzProgress(pkg.Fuzz, event.Test, event.Output)
+		}
+		if p.parseBenchmarkOutput(key, event) != nil && !isTestDone(result) {
+			// Passing benchmarks don't report a pass action; the result line completes them
+			p.handleTestCompletion(result, pkg, TestEvent{Action: "pass", Package: event.Package, Test: event.Test})
+		}
+	case "bench":
+		// Benchmark that logged output but did not fail
+		if event.Output != "" {
+			p.handleTestOutput(result, event)
+		}
+		if !isTestDone(result) {
+			p.handleTestCompletion(result, pkg, TestEvent{Action: "pass", Package: event.Package, Test: event.Test, Elapsed: event.Elapsed})
+		}
 	case "pass", "fail", "skip":
 		p.handleTestCompletion(result, pkg, event)
 	}
 }
 
+func isTestDone(result *TestResult) bool {
+	return result.Passed || result.Failed || result.Skipped
+}
+
+// parseBenchmarkOutput records a benchmark result line from test or package output.
+// Result lines may be split across several output events, so unterminated
+// lines starting with "Benchmark" are buffered until the newline arrives.
+func (p *DefaultEventProcessor) parseBenchmarkOutput(key string, event TestEvent) *BenchmarkResult {
+	line := p.partialLines[key] + event.Output
+	delete(p.partialLines, key)
+
+	if !strings.HasPrefix(line, "Benchmark") {
+		return nil
+	}
+	if !strings.HasSuffix(line, "\n") {
+		p.partialLines[key] = line
+		return nil
+	}
+
+	benchmark := parseBenchmarkLine(line)
+	if benchmark == nil {
+		return nil
+	}
+	benchmark.Package = event.Package
+	p.benchmarks = append(p.benchmarks, benchmark)
+	return benchmark
+}
+
+// finishBenchmarks marks benchmarks still running in the package as passed.
+// Benchmarks never report a pass action, so the end of the package completes them.
+func (p *DefaultEventProcessor) finishBenchmarks(pkg *PackageState) {
+	for _, result := range p.results {
+		if result.Package != pkg.Name || !isBenchmarkName(result.Test) || !result.Started || isTestDone(result) {
+			continue
+		}
+		p.handleTestCompletion(result, pkg, TestEvent{Action: "pass", Package: result.Package, Test: result.Test})
+	}
+}
+
 func (p *DefaultEventProcessor) ensureTestResult(key string, event TestEvent) *TestResult {
 	if _, exists := p.results[key]; !exists {
 		p.results[key] = &TestResult{
@@ -136,6 +246,9 @@ func (p *DefaultEventProcessor) handleTestRun(result *TestResult, pkg *PackageSt
 
 func (p *DefaultEventProcessor) handleTestOutput(result *TestResult, event TestEvent) {
 	result.Output = append(result.Output, event.Output)
+	if input, ok := parseFuzzInput(event.Output); ok {
+		result.FuzzInput = input
+	}
 	if result.Location == "" {
 		if location := extractFileLocationWithPackage(event.Output, event.Package); location != "" {
 			result.Location = location
@@ -146,6 +259,9 @@ func (p *DefaultEventProcessor) handleTestOutput(result *TestResult, event TestE
 func (p *DefaultEventProcessor) handleTestCompletion(result *TestResult, pkg *PackageState, event TestEvent) {
 	result.Elapsed = event.Elapsed
 	pkg.Running--
+	if pkg.Fuzz != nil && pkg.Fuzz.Test == event.Test {
+		pkg.Fuzz = nil
+	}
 
 	isParentWithSubtests := p.isParentWithSubtests(event.Test, event.Package)
 
@@ -174,10 +290,19 @@ func (p *DefaultEventProcessor) processPackageEvent(event TestEvent) {
 	switch event.Action {
 	case "output":
 		pkg.Output = append(pkg.Output, event.Output)
-	case "pass":
+		p.parseBenchmarkOutput(event.Package, event)
+		if coverage, ok := parseCoverageLine(event.Output); ok {
+			pkg.Coverage = coverage
+			pkg.HasCoverage = true
+		}
+	case "pass", "skip":
 		pkg.Elapsed = event.Elapsed
+		pkg.Completed = true
+		p.finishBenchmarks(pkg)
 	case "fail":
 		pkg.Elapsed = event.Elapsed
+		pkg.Completed = true
+		p.finishBenchmarks(pkg)
 		key := fmt.Sprintf("%s/[PACKAGE]", event.Package)
 		p.results[key] = &TestResult{
 			Package: event.Package,
@@ -303,6 +303,58 @@ func TestEventProcessor_ProcessPackageEvent(t *testing.T) {
 	if pkg.Elapsed != 1.5 {
 		t.Errorf("Expected package elapsed time 1.5, got %f", pkg.Elapsed)
 	}
+
+	if !pkg.Completed {
+		t.Error("Expected package to be marked as completed")
+	}
+}
+
+func TestEventProcessor_ProcessPackageEvent_Incomplete(t *testing.T) {
+	t.Parallel()
+	processor := NewEventProcessor()
+
+	processor.ProcessEvent(TestEvent{Action: "start", Package: "example"})
+	processor.ProcessEvent(TestEvent{Action: "run", Package: "example", Test: "TestExample"})
+
+	if processor.GetPackages()["example"].Completed {
+		t.Error("Package without a final action should not be completed")
+	}
+
+	processor.ProcessEvent(TestEvent{Action: "skip", Package: "example"})
+
+	if !processor.GetPackages()["example"].Completed {
+		t.Error("Package skip should mark the package as completed")
+	}
+}
+
+func TestEventProcessor_Finalize(t *testing.T) {
+	t.Parallel()
+	processor := NewEventProcessor()
+
+	processor.ProcessEvent(TestEvent{Action: "start", Package: "example"})
+	processor.ProcessEvent(TestEvent{Action: "run", Package: "example", Test: "TestDone"})
+	processor.ProcessEvent(TestEvent{Action: "pass", Package: "example", Test: "TestDone"})
+	processor.ProcessEvent(TestEvent{Action: "run", Package: "example", Test: "TestHanging"})
+	processor.ProcessEvent(TestEvent{Action: "start", Package: "other"})
+	processor.ProcessEvent(TestEvent{Action: "pass", Package: "other"})
+
+	processor.Finalize()
+
+	packages := processor.GetPackages()
+	results := processor.GetResults()
+
+	if !packages["example"].Incomplete {
+		t.Error("Package without a final action should be incomplete")
+	}
+	if packages["other"].Incomplete {
+		t.Error("Completed package should not be incomplete")
+	}
+	if !results["example/TestHanging"].Incomplete {
+		t.Error("Running test should be incomplete")
+	}
+	if results["example/TestDone"].Incomplete {
+		t.Error("Finished test should not be incomplete")
+	}
 }
 
 func TestEventProcessor_ProcessPackageEvent_Fail(t *testing.T) {
@@ -482,3 +534,52 @@ func TestShouldDisplayPackageFailure(t *testing.T) {
 		})
 	}
 }
+
+func TestEventProcessor_Benchmarks(t *testing.T) {
+	t.Parallel()
+	processor := NewEventProcessor()
+
+	events := []TestEvent{
+		{Action: "start", Package: "example"},
+		{Action: "run", Package: "example", Test: "BenchmarkJoin"},
+		{Action: "output", Package: "example", Test: "BenchmarkJoin", Output: "BenchmarkJoin\n"},
+		// Result line split across two output events
+		{Action: "output", Package: "example", Test: "BenchmarkJoin", Output: "BenchmarkJoin-8   \t"},
+		{Action: "output", Package: "example", Test: "BenchmarkJoin", Output: "    1000\t       188.0 ns/op\t      16 B/op\n"},
+		// Second sample (-count 2) attributed to the package
+		{Action: "output", Package: "example", Output: "BenchmarkJoin-8   \t    1000\t       174.8 ns/op\t      16 B/op\n"},
+		{Action: "run", Package: "example", Test: "BenchmarkSub"},
+		{Action: "run", Package: "example", Test: "BenchmarkSub/small"},
+		{Action: "bench", Package: "example", Test: "BenchmarkSub/small"},
+		{Action: "pass", Package: "example", Elapsed: 1.0},
+	}
+	for _, event := range events {
+		processor.ProcessEvent(event)
+	}
+
+	benchmarks := processor.GetBenchmarks()
+	if len(benchmarks) != 2 {
+		t.Fatalf("Expected 2 benchmark samples, got %d", len(benchmarks))
+	}
+	if benchmarks[0].Name != "BenchmarkJoin" || benchmarks[0].Metrics["ns/op"] != 188.0 || benchmarks[0].Package != "example" {
+		t.Errorf("Unexpected first sample: %+v", benchmarks[0])
+	}
+	if benchmarks[1].Metrics["ns/op"] != 174.8 {
+		t.Errorf("Unexpected second sample: %+v", benchmarks[1])
+	}
+
+	results := processor.GetResults()
+	for _, name := range []string{"BenchmarkJoin", "BenchmarkSub", "BenchmarkSub/small"} {
+		if !results["example/"+name].Passed {
+			t.Errorf("Expected %s to be passed", name)
+		}
+	}
+
+	pkg := processor.GetPackages()["example"]
+	if pkg.Running != 0 {
+		t.Errorf("Expected 0 running benchmarks, got %d", pkg.Running)
+	}
+	if pkg.Passed != 2 {
+		t.Errorf("Expected 2 passed benchmarks, got %d", pkg.Passed)
+	}
+}
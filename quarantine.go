@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QuarantineEntry describes a known-broken test whose failure should not fail the build
+type QuarantineEntry struct {
+	Package string `json:"package"` // Regexp matched against the full package import path (empty matches all)
+	Test    string `json:"test"`    // Regexp matched against the test name or any of its parents
+	Reason  string `json:"reason"`  // Why the test is quarantined (e.g., a ticket link)
+	Expires string `json:"expires"` // Last day the entry is valid (YYYY-MM-DD, empty for no expiry)
+
+	packageRe *regexp.Regexp
+	testRe    *regexp.Regexp
+	expiresAt time.Time
+}
+
+// Quarantine holds the list of quarantined tests
+type Quarantine struct {
+	Entries []*QuarantineEntry `json:"quarantine"`
+}
+
+// LoadQuarantine reads a quarantine file in JSON format
+func LoadQuarantine(path string) (*Quarantine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading quarantine file: %w", err)
+	}
+	return ParseQuarantine(data)
+}
+
+// ParseQuarantine parses quarantine file content and compiles its patterns
+func ParseQuarantine(data []byte) (*Quarantine, error) {
+	var q Quarantine
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("parsing quarantine file: %w", err)
+	}
+
+	for i, entry := range q.Entries {
+		if entry.Test == "" {
+			return nil, fmt.Errorf("quarantine entry %d: test pattern is required", i+1)
+		}
+
+		var err error
+		if entry.testRe, err = compileAnchored(entry.Test); err != nil {
+			return nil, fmt.Errorf("quarantine entry %d: invalid test pattern: %w", i+1, err)
+		}
+		if entry.Package != "" {
+			if entry.packageRe, err = compileAnchored(entry.Package); err != nil {
+				return nil, fmt.Errorf("quarantine entry %d: invalid package pattern: %w", i+1, err)
+			}
+		}
+		if entry.Expires != "" {
+			if entry.expiresAt, err = time.Parse(time.DateOnly, entry.Expires); err != nil {
+				return nil, fmt.Errorf("quarantine entry %d: invalid expiry date: %w", i+1, err)
+			}
+		}
+	}
+	return &q, nil
+}
+
+func compileAnchored(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// IsExpired reports whether the entry is no longer valid at the given time
+func (e *QuarantineEntry) IsExpired(now time.Time) bool {
+	if e.expiresAt.IsZero() {
+		return false
+	}
+	return !now.Before(e.expiresAt.AddDate(0, 0, 1))
+}
+
+// Matches reports whether the entry covers the given test.
+// A test is covered if its own name or the name of any parent test matches.
+func (e *QuarantineEntry) Matches(packageName, testName string) bool {
+	return matchesTestOrParent(e.packageRe, e.testRe, packageName, testName)
+}
+
+// matchesTestOrParent reports whether the package matches packageRe and the
+// test or any of its parent tests matches testRe. A nil pattern matches anything.
+func matchesTestOrParent(packageRe, testRe *regexp.Regexp, packageName, testName string) bool {
+	if packageRe != nil && !packageRe.MatchString(packageName) {
+		return false
+	}
+	if testRe == nil {
+		return true
+	}
+
+	name := testName
+	for {
+		if testRe.MatchString(name) {
+			return true
+		}
+		idx := strings.LastIndex(name, "/")
+		if idx == -1 {
+			return false
+		}
+		name = name[:idx]
+	}
+}
+
+// Match returns the first entry covering the given test, or nil
+func (q *Quarantine) Match(packageName, testName string) *QuarantineEntry {
+	if q == nil {
+		return nil
+	}
+	for _, entry := range q.Entries {
+		if entry.Matches(packageName, testName) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// quarantinedTest is a failed test excluded from the exit code by a quarantine entry
+type quarantinedTest struct {
+	result *TestResult
+	entry  *QuarantineEntry
+}
+
+// quarantineReport is the outcome of applying the quarantine list to a run
+type quarantineReport struct {
+	packages         map[string]*PackageState
+	results          map[string]*TestResult
+	quarantined      []quarantinedTest
+	unexpectedlyPass []*QuarantineEntry
+	expired          []*QuarantineEntry
+}
+
+// applyQuarantine returns copies of packages and results in which failures of
+// quarantined tests are no longer counted as failures. Expired entries are
+// ignored for matching and reported separately.
+func applyQuarantine(q *Quarantine, packages map[string]*PackageState, results map[string]*TestResult, now time.Time) quarantineReport {
+	report := quarantineReport{packages: packages, results: results}
+	if q == nil || len(q.Entries) == 0 {
+		return report
+	}
+
+	active := &Quarantine{}
+	for _, entry := range q.Entries {
+		if entry.IsExpired(now) {
+			report.expired = append(report.expired, entry)
+		} else {
+			active.Entries = append(active.Entries, entry)
+		}
+	}
+
+	report.packages = make(map[string]*PackageState, len(packages))
+	for name, pkg := range packages {
+		copied := *pkg
+		report.packages[name] = &copied
+	}
+	report.results = make(map[string]*TestResult, len(results))
+
+	// Skipped tests and tests that didn't finish neither passed nor failed
+	passed := make(map[*QuarantineEntry]bool)
+	failed := make(map[*QuarantineEntry]bool)
+	quarantinedKeys := make(map[string]bool)
+
+	for key, result := range results {
+		var entry *QuarantineEntry
+		if !isSyntheticResult(result) {
+			entry = active.Match(result.Package, result.Test)
+		}
+		if entry == nil {
+			report.results[key] = result
+			continue
+		}
+
+		if !result.Failed {
+			passed[entry] = passed[entry] || result.Passed
+			report.results[key] = result
+			continue
+		}
+		failed[entry] = true
+		quarantinedKeys[key] = true
+
+		copied := *result
+		copied.Failed = false
+		report.results[key] = &copied
+
+		if result.HasSubtest {
+			continue
+		}
+		report.quarantined = append(report.quarantined, quarantinedTest{result: result, entry: entry})
+		if pkg, exists := report.packages[result.Package]; exists {
+			pkg.Failed--
+			pkg.IndividualTestFailed--
+		}
+	}
+
+	clearQuarantinedParents(report.results, quarantinedKeys)
+
+	for _, entry := range active.Entries {
+		if passed[entry] && !failed[entry] {
+			report.unexpectedlyPass = append(report.unexpectedlyPass, entry)
+		}
+	}
+
+	sort.Slice(report.quarantined, func(i, j int) bool {
+		a, b := report.quarantined[i].result, report.quarantined[j].result
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Test < b.Test
+	})
+
+	return report
+}
+
+// clearQuarantinedParents clears the failure of parent tests that only failed
+// because of quarantined subtests: every failing subtest is quarantined and
+// the parent reported no failure of its own. Parents are not counted in the
+// package totals, so those don't change.
+func clearQuarantinedParents(results map[string]*TestResult, quarantined map[string]bool) {
+	var parents []string
+	for key, result := range results {
+		if result.Failed && result.HasSubtest {
+			parents = append(parents, key)
+		}
+	}
+	// Deepest first, so that a cleared parent counts as quarantined for its own parent
+	sort.Slice(parents, func(i, j int) bool {
+		return strings.Count(results[parents[i]].Test, "/") > strings.Count(results[parents[j]].Test, "/")
+	})
+
+	for _, key := range parents {
+		parent := results[key]
+		prefix := parent.Test + "/"
+		hasQuarantined, hasFailed := false, false
+		for subKey, result := range results {
+			if result.Package != parent.Package || !strings.HasPrefix(result.Test, prefix) {
+				continue
+			}
+			hasFailed = hasFailed || result.Failed
+			hasQuarantined = hasQuarantined || quarantined[subKey]
+		}
+		if !hasQuarantined || hasFailed || hasOwnFailureOutput(parent.Output) {
+			continue
+		}
+
+		copied := *parent
+		copied.Failed = false
+		results[key] = &copied
+		quarantined[key] = true
+	}
+}
+
+// hasOwnFailureOutput reports whether a test printed anything besides the
+// lines go test writes for every test, such as a t.Error message
+func hasOwnFailureOutput(output []string) bool {
+	for _, line := range output {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "=== ") && !strings.HasPrefix(trimmed, "--- ") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFindings reports whether there is anything to show in the quarantine section
+func (r quarantineReport) hasFindings() bool {
+	return len(r.quarantined) > 0 || len(r.unexpectedlyPass) > 0 || len(r.expired) > 0
+}
+
+// describe returns a short human-readable description of the entry
+func (e *QuarantineEntry) describe() string {
+	desc := e.Test
+	if e.Package != "" {
+		desc = e.Package + " " + desc
+	}
+	return desc
+}
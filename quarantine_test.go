@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseQuarantine(t *testing.T, content string) *Quarantine {
+	t.Helper()
+	q, err := ParseQuarantine([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseQuarantine() error = %v", err)
+	}
+	return q
+}
+
+func TestParseQuarantine_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{`},
+		{"missing test pattern", `{"quarantine":[{"package":"example"}]}`},
+		{"invalid test pattern", `{"quarantine":[{"test":"Test("}]}`},
+		{"invalid package pattern", `{"quarantine":[{"package":"(","test":"TestA"}]}`},
+		{"invalid expiry date", `{"quarantine":[{"test":"TestA","expires":"next week"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseQuarantine([]byte(tt.content)); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestQuarantineEntry_Matches(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[
+		{"package":".*/example","test":"TestDivide"},
+		{"test":"TestFlaky.*"}
+	]}`)
+
+	tests := []struct {
+		pkg      string
+		test     string
+		expected bool
+	}{
+		{"github.com/Sixeight/gotestshow/example", "TestDivide", true},
+		{"github.com/Sixeight/gotestshow/example", "TestDivide/divide_by_zero", true},
+		{"github.com/Sixeight/gotestshow/example", "TestDivideAll", false},
+		{"github.com/Sixeight/gotestshow/other", "TestDivide", false},
+		{"any/package", "TestFlakyNetwork", true},
+		{"any/package", "TestNotFlaky", false},
+	}
+
+	for _, tt := range tests {
+		if got := q.Match(tt.pkg, tt.test) != nil; got != tt.expected {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pkg, tt.test, got, tt.expected)
+		}
+	}
+}
+
+func TestQuarantineEntry_IsExpired(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[{"test":"TestA","expires":"2025-06-30"}]}`)
+	entry := q.Entries[0]
+
+	if entry.IsExpired(time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC)) {
+		t.Error("entry should still be valid on its expiry day")
+	}
+	if !entry.IsExpired(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("entry should be expired the day after its expiry date")
+	}
+}
+
+func TestApplyQuarantine(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[
+		{"test":"TestBroken","reason":"TICKET-1"},
+		{"test":"TestFixed","reason":"TICKET-2"},
+		{"test":"TestOld","expires":"2020-01-01"},
+		{"test":"TestSkipped","reason":"TICKET-3"},
+		{"test":"TestHangs","reason":"TICKET-4"}
+	]}`)
+
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 6, Passed: 1, Failed: 3, Skipped: 1, IndividualTestFailed: 3},
+	}
+	results := map[string]*TestResult{
+		"example/TestBroken":  {Package: "example", Test: "TestBroken", Failed: true},
+		"example/TestFixed":   {Package: "example", Test: "TestFixed", Passed: true},
+		"example/TestOld":     {Package: "example", Test: "TestOld", Failed: true},
+		"example/TestReal":    {Package: "example", Test: "TestReal", Failed: true},
+		"example/TestSkipped": {Package: "example", Test: "TestSkipped", Skipped: true},
+		"example/TestHangs":   {Package: "example", Test: "TestHangs", Started: true, Incomplete: true},
+	}
+
+	report := applyQuarantine(q, packages, results, time.Now())
+
+	if len(report.quarantined) != 1 || report.quarantined[0].result.Test != "TestBroken" {
+		t.Fatalf("Expected TestBroken to be quarantined, got %+v", report.quarantined)
+	}
+	if report.results["example/TestBroken"].Failed {
+		t.Error("Quarantined failure should not be counted as failed")
+	}
+	if !results["example/TestBroken"].Failed {
+		t.Error("Original results should not be modified")
+	}
+	if !report.results["example/TestOld"].Failed {
+		t.Error("Expired entries should not quarantine failures")
+	}
+	if report.packages["example"].Failed != 2 {
+		t.Errorf("Expected 2 failures after quarantine, got %d", report.packages["example"].Failed)
+	}
+	if packages["example"].Failed != 3 {
+		t.Error("Original packages should not be modified")
+	}
+	if len(report.unexpectedlyPass) != 1 || report.unexpectedlyPass[0].Test != "TestFixed" {
+		t.Errorf("Expected only the TestFixed entry to be flagged as passing, not skipped or unfinished tests, got %+v", report.unexpectedlyPass)
+	}
+	if len(report.expired) != 1 || report.expired[0].Test != "TestOld" {
+		t.Errorf("Expected TestOld entry to be expired, got %+v", report.expired)
+	}
+}
+
+func TestApplyQuarantine_Subtest(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[
+		{"test":"TestA/sub","reason":"TICKET-1"},
+		{"test":"TestB/sub","reason":"TICKET-2"}
+	]}`)
+
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 5, Failed: 2, Passed: 1, IndividualTestFailed: 2},
+	}
+	results := map[string]*TestResult{
+		"example/TestA": {Package: "example", Test: "TestA", Failed: true, HasSubtest: true,
+			Output: []string{"=== RUN   TestA\n", "--- FAIL: TestA (0.00s)\n"}},
+		"example/TestA/sub": {Package: "example", Test: "TestA/sub", Failed: true},
+		"example/TestA/ok":  {Package: "example", Test: "TestA/ok", Passed: true},
+		"example/TestB": {Package: "example", Test: "TestB", Failed: true, HasSubtest: true,
+			Output: []string{"=== RUN   TestB\n", "    b_test.go:10: setup failed\n", "--- FAIL: TestB (0.00s)\n"}},
+		"example/TestB/sub": {Package: "example", Test: "TestB/sub", Failed: true},
+	}
+
+	report := applyQuarantine(q, packages, results, time.Now())
+
+	if report.results["example/TestA"].Failed {
+		t.Error("Parent whose only failing subtest is quarantined should not be failed")
+	}
+	if !results["example/TestA"].Failed {
+		t.Error("Original results should not be modified")
+	}
+	if !report.results["example/TestB"].Failed {
+		t.Error("Parent with a failure of its own should stay failed")
+	}
+	if report.packages["example"].Failed != 0 {
+		t.Errorf("Expected no failures after quarantine, got %d", report.packages["example"].Failed)
+	}
+
+	delete(report.results, "example/TestB")
+	if commands := buildRerunCommands(report.results); len(commands) != 0 {
+		t.Errorf("Expected no rerun commands, got %v", commands)
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_Quarantine(t *testing.T) {
+	t.Parallel()
+	for _, ci := range []bool{false, true} {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, false)
+		display.SetConfig(&Config{
+			CIMode:     ci,
+			Quarantine: mustParseQuarantine(t, `{"quarantine":[{"test":"TestBroken","reason":"TICKET-1"}]}`),
+		})
+
+		packages := map[string]*PackageState{
+			"example": {Name: "example", Total: 2, Passed: 1, Failed: 1, IndividualTestFailed: 1},
+		}
+		results := map[string]*TestResult{
+			"example/TestBroken": {Package: "example", Test: "TestBroken", Failed: true},
+			"example/TestOK":     {Package: "example", Test: "TestOK", Passed: true},
+		}
+
+		exitCode := display.ShowFinalResults(packages, results, nil, time.Now())
+
+		output := buf.String()
+		if exitCode != 0 {
+			t.Errorf("CI=%v: expected exit code 0 for quarantined failure, got %d", ci, exitCode)
+		}
+		if !strings.Contains(output, "Quarantined") || !strings.Contains(output, "TICKET-1") {
+			t.Errorf("CI=%v: output should contain the quarantine section, got:\n%s", ci, output)
+		}
+		if !strings.Contains(output, "All tests passed") {
+			t.Errorf("CI=%v: output should report success, got:\n%s", ci, output)
+		}
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_ExpiredQuarantine(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, false)
+	display.SetConfig(&Config{
+		Quarantine: mustParseQuarantine(t, `{"quarantine":[{"test":"TestBroken","expires":"2020-01-01"}]}`),
+	})
+
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 1, Passed: 1},
+	}
+	results := map[string]*TestResult{
+		"example/TestOK": {Package: "example", Test: "TestOK", Passed: true},
+	}
+
+	if exitCode := display.ShowFinalResults(packages, results, nil, time.Now()); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for expired quarantine entry, got %d", exitCode)
+	}
+	if !strings.Contains(buf.String(), "Expired quarantine entries") {
+		t.Error("Output should list expired quarantine entries")
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// runReport is the data rendered into the HTML and Markdown reports
+type runReport struct {
+	GeneratedAt string
+	Duration    string
+	AllPassed   bool
+	Total       int
+	Passed      int
+	Failed      int
+	Skipped     int
+	Packages    []reportPackage
+	HasCoverage bool // Whether any package reported coverage
+	Tests       []reportTest
+	SlowTests   []reportTest
+	Histogram   []reportBucket
+}
+
+type reportPackage struct {
+	Name    string
+	Status  string
+	Total   int
+	Passed  int
+	Failed  int
+	Skipped int
+	Elapsed float64
+
+	Coverage    float64
+	HasCoverage bool
+}
+
+type reportTest struct {
+	Package   string
+	Name      string
+	Status    string
+	Location  string
+	Elapsed   float64
+	Threshold string // Threshold the test was judged against, for slow tests
+	Output    string // Relevant output of failed tests
+	Reason    string // Reason of the quarantine entry, for quarantined tests
+}
+
+// reportBucket is one bar of the duration histogram
+type reportBucket struct {
+	Label   string
+	Count   int
+	Percent float64 // Bar length relative to the largest bucket
+}
+
+// histogramBounds are the upper bounds of the duration histogram buckets
+var histogramBounds = []struct {
+	label string
+	upper time.Duration
+}{
+	{"< 10ms", 10 * time.Millisecond},
+	{"10ms – 100ms", 100 * time.Millisecond},
+	{"100ms – 500ms", 500 * time.Millisecond},
+	{"500ms – 1s", time.Second},
+	{"1s – 5s", 5 * time.Second},
+	{"5s – 10s", 10 * time.Second},
+	{"≥ 10s", 0},
+}
+
+// buildRunReport collects the report data from the final results with the
+// quarantine applied, so quarantined failures are reported as such
+func buildRunReport(run quarantineReport, config *Config, elapsed time.Duration, exitCode int) runReport {
+	packages, results := run.packages, run.results
+	quarantined := make(map[string]*QuarantineEntry, len(run.quarantined))
+	for _, test := range run.quarantined {
+		quarantined[test.result.Package+"/"+test.result.Test] = test.entry
+	}
+
+	stats := collectSummaryStats(packages, results)
+	report := runReport{
+		GeneratedAt: time.Now().Format(time.RFC1123),
+		Duration:    formatDuration(elapsed.Seconds()),
+		AllPassed:   exitCode == 0,
+		Total:       stats.totalTests,
+		Passed:      stats.totalPassed,
+		Failed:      stats.totalFailed,
+		Skipped:     stats.totalSkipped,
+	}
+
+	for _, name := range sortedPackageNames(packages) {
+		pkg := packages[name]
+		report.Packages = append(report.Packages, reportPackage{
+			Name:    name,
+			Status:  packageStatus(pkg, results),
+			Total:   pkg.Total,
+			Passed:  pkg.Passed,
+			Failed:  pkg.Failed,
+			Skipped: pkg.Skipped,
+			Elapsed: pkg.Elapsed,
+
+			Coverage:    pkg.Coverage,
+			HasCoverage: pkg.HasCoverage,
+		})
+		report.HasCoverage = report.HasCoverage || pkg.HasCoverage
+	}
+
+	counts := make([]int, len(histogramBounds))
+	for _, result := range sortedResults(results) {
+		if result.HasSubtest {
+			continue
+		}
+		if result.Test == "[PACKAGE]" {
+			if pkg, exists := packages[result.Package]; exists && !shouldDisplayPackageFailure(pkg) {
+				continue
+			}
+		}
+
+		test := reportTest{
+			Package:  result.Package,
+			Name:     result.Test,
+			Status:   resultStatus(result),
+			Location: result.Location,
+			Elapsed:  result.Elapsed,
+		}
+		if entry, exists := quarantined[result.Package+"/"+result.Test]; exists {
+			test.Status = "quarantined"
+			test.Reason = entry.Reason
+		}
+		if result.Failed || test.Status == "quarantined" {
+			test.Output = strings.Join(extractRelevantOutput(result.Output), "")
+		}
+		report.Tests = append(report.Tests, test)
+
+		if isSyntheticResult(result) {
+			continue
+		}
+		counts[histogramBucket(result.Elapsed)]++
+
+		if config != nil {
+			threshold, _ := thresholdFor(config.Thresholds, config.Threshold, result.Package, result.Test)
+			if exceedsThreshold(result.Elapsed, threshold) {
+				test.Threshold = threshold.String()
+				report.SlowTests = append(report.SlowTests, test)
+			}
+		}
+	}
+
+	sort.SliceStable(report.SlowTests, func(i, j int) bool {
+		return report.SlowTests[i].Elapsed > report.SlowTests[j].Elapsed
+	})
+
+	largest := 0
+	for _, count := range counts {
+		largest = max(largest, count)
+	}
+	for i, bound := range histogramBounds {
+		bucket := reportBucket{Label: bound.label, Count: counts[i]}
+		if largest > 0 {
+			bucket.Percent = float64(counts[i]) / float64(largest) * 100
+		}
+		report.Histogram = append(report.Histogram, bucket)
+	}
+
+	return report
+}
+
+// histogramBucket returns the index of the histogram bucket for a duration in seconds
+func histogramBucket(elapsed float64) int {
+	d := time.Duration(elapsed * float64(time.Second))
+	for i, bound := range histogramBounds {
+		if bound.upper == 0 || d < bound.upper {
+			return i
+		}
+	}
+	return len(histogramBounds) - 1
+}
+
+func resultStatus(result *TestResult) string {
+	switch {
+	case result.Failed:
+		return "fail"
+	case result.Skipped:
+		return "skip"
+	case result.Passed:
+		return "pass"
+	case result.Incomplete:
+		return "incomplete"
+	default:
+		return "running"
+	}
+}
+
+func packageStatus(pkg *PackageState, results map[string]*TestResult) string {
+	switch {
+	case pkg.Failed > 0 || hasSyntheticFailure(pkg.Name, results):
+		return "fail"
+	case pkg.Incomplete:
+		return "incomplete"
+	case pkg.Total == 0:
+		return "skip"
+	default:
+		return "pass"
+	}
+}
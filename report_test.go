@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildRunReport(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 4, Passed: 2, Failed: 1, Skipped: 1, Elapsed: 2.5, IndividualTestFailed: 1, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestFast":      {Package: "example", Test: "TestFast", Passed: true, Elapsed: 0.005},
+		"example/TestSlow":      {Package: "example", Test: "TestSlow", Passed: true, Elapsed: 1.2},
+		"example/TestFail":      {Package: "example", Test: "TestFail", Failed: true, Elapsed: 0.2, Location: "a_test.go:7", Output: []string{"=== RUN   TestFail\n", "    a_test.go:7: boom\n"}},
+		"example/TestSkip":      {Package: "example", Test: "TestSkip", Skipped: true},
+		"example/TestParent":    {Package: "example", Test: "TestParent", Passed: true, HasSubtest: true, Elapsed: 1.2},
+		"example/TestParent/a":  {Package: "example", Test: "TestParent/a", Passed: true, Elapsed: 0.05},
+		"example/[PACKAGE]":     {Package: "example", Test: "[PACKAGE]", Failed: true},
+		"example/TestUnrelated": {Package: "example", Test: "TestUnrelated", Passed: true, Elapsed: 12},
+	}
+	config := &Config{Threshold: time.Second}
+
+	report := buildRunReport(applyQuarantine(nil, packages, results, time.Now()), config, 3*time.Second, 1)
+
+	if report.AllPassed || report.Total != 4 || report.Failed != 1 || report.Duration != "3.000s" {
+		t.Errorf("unexpected summary: %+v", report)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].Status != "fail" {
+		t.Errorf("unexpected packages: %+v", report.Packages)
+	}
+
+	// Parent tests and package failures caused by failing tests are left out
+	if len(report.Tests) != 6 {
+		t.Errorf("expected 6 tests, got %d: %+v", len(report.Tests), report.Tests)
+	}
+	for _, test := range report.Tests {
+		if test.Name == "TestFail" && test.Output != "    a_test.go:7: boom\n" {
+			t.Errorf("failed test output = %q", test.Output)
+		}
+	}
+
+	if len(report.SlowTests) != 2 || report.SlowTests[0].Name != "TestUnrelated" || report.SlowTests[1].Threshold != "1s" {
+		t.Errorf("unexpected slow tests: %+v", report.SlowTests)
+	}
+
+	expectedCounts := map[string]int{"< 10ms": 2, "10ms – 100ms": 1, "100ms – 500ms": 1, "1s – 5s": 1, "≥ 10s": 1}
+	for _, bucket := range report.Histogram {
+		if bucket.Count != expectedCounts[bucket.Label] {
+			t.Errorf("histogram %q = %d, want %d", bucket.Label, bucket.Count, expectedCounts[bucket.Label])
+		}
+	}
+}
+
+func TestBuildRunReport_Quarantine(t *testing.T) {
+	t.Parallel()
+	q := mustParseQuarantine(t, `{"quarantine":[{"test":"TestFlaky","reason":"TICKET-1"}]}`)
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 2, Passed: 1, Failed: 1, IndividualTestFailed: 1, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestPass":  {Package: "example", Test: "TestPass", Passed: true},
+		"example/TestFlaky": {Package: "example", Test: "TestFlaky", Failed: true, Output: []string{"    a_test.go:9: flaky\n"}},
+	}
+
+	report := buildRunReport(applyQuarantine(q, packages, results, time.Now()), &Config{}, time.Second, 0)
+
+	if report.Failed != 0 || report.Packages[0].Status != "pass" {
+		t.Errorf("quarantined failure should not fail the report: %+v", report)
+	}
+	for _, test := range report.Tests {
+		if test.Name == "TestFlaky" && (test.Status != "quarantined" || test.Reason != "TICKET-1" || test.Output != "    a_test.go:9: flaky\n") {
+			t.Errorf("unexpected quarantined test: %+v", test)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// rerunCommand is a ready-to-run `go test` invocation for failed tests in a package
+type rerunCommand struct {
+	Package string
+	Pattern string
+	Command string
+}
+
+// buildRerunCommands creates `go test -run` commands for every failed test,
+// grouped by package. Packages are passed by import path, which go test
+// resolves from anywhere inside the module.
+func buildRerunCommands(results map[string]*TestResult) []rerunCommand {
+	// package -> parent path -> leaf names
+	failed := make(map[string]map[string][]string)
+
+	// A parent is rerun through its failed subtests. One that failed on its
+	// own after all its subtests passed, e.g. with t.Fatal after t.Run, is
+	// rerun itself.
+	failedParents := make(map[string]bool)
+	for _, result := range results {
+		if !result.Failed {
+			continue
+		}
+		for parent, _ := splitTestName(result.Test); parent != ""; parent, _ = splitTestName(parent) {
+			failedParents[result.Package+"/"+parent] = true
+		}
+	}
+
+	for _, result := range results {
+		if !result.Failed || failedParents[result.Package+"/"+result.Test] || isSyntheticResult(result) {
+			continue
+		}
+
+		name := result.Test
+		if result.FuzzInput != "" {
+			// Rerun the fuzz target with the input that failed
+			name += "/" + path.Base(result.FuzzInput)
+		}
+		parent, leaf := splitTestName(name)
+		if _, exists := failed[result.Package]; !exists {
+			failed[result.Package] = make(map[string][]string)
+		}
+		failed[result.Package][parent] = append(failed[result.Package][parent], leaf)
+	}
+
+	packageNames := make([]string, 0, len(failed))
+	for pkgName := range failed {
+		packageNames = append(packageNames, pkgName)
+	}
+	sort.Strings(packageNames)
+
+	var commands []rerunCommand
+	for _, pkgName := range packageNames {
+		parents := make([]string, 0, len(failed[pkgName]))
+		for parent := range failed[pkgName] {
+			parents = append(parents, parent)
+		}
+		sort.Strings(parents)
+
+		for _, parent := range parents {
+			leaves := failed[pkgName][parent]
+			sort.Strings(leaves)
+
+			pattern := buildRunPattern(parent, leaves)
+			commands = append(commands, rerunCommand{
+				Package: pkgName,
+				Pattern: pattern,
+				Command: fmt.Sprintf("go test -run %s %s", shellQuote(pattern), pkgName),
+			})
+		}
+	}
+	return commands
+}
+
+// isSyntheticResult reports whether the result was created for a build or package failure
+func isSyntheticResult(result *TestResult) bool {
+	return result.Test == "[BUILD]" || result.Test == "[PACKAGE]"
+}
+
+// splitTestName splits a test name into its parent path and the last element
+func splitTestName(name string) (string, string) {
+	idx := strings.LastIndex(name, "/")
+	if idx == -1 {
+		return "", name
+	}
+	return name[:idx], name[idx+1:]
+}
+
+// buildRunPattern builds a -run pattern matching the given leaves under parent.
+// Each level is anchored so that e.g. TestA does not also match TestAB.
+func buildRunPattern(parent string, leaves []string) string {
+	var levels []string
+	if parent != "" {
+		for _, elem := range strings.Split(parent, "/") {
+			levels = append(levels, "^"+regexp.QuoteMeta(elem)+"$")
+		}
+	}
+
+	quoted := make([]string, len(leaves))
+	for i, leaf := range leaves {
+		quoted[i] = regexp.QuoteMeta(leaf)
+	}
+	if len(quoted) == 1 {
+		levels = append(levels, "^"+quoted[0]+"$")
+	} else {
+		levels = append(levels, "^("+strings.Join(quoted, "|")+")$")
+	}
+
+	return strings.Join(levels, "/")
+}
+
+// shellQuote wraps s in single quotes for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// writeRerunScript writes the rerun commands to an executable shell script.
+// Without commands the script does nothing, so a script left over from an
+// earlier run doesn't rerun tests that pass now.
+func writeRerunScript(path string, commands []rerunCommand) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Generated by gotestshow: reruns the tests that failed\n")
+	if len(commands) == 0 {
+		b.WriteString("# No tests failed\n")
+	}
+	for _, cmd := range commands {
+		b.WriteString(cmd.Command)
+		b.WriteString("\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o755); err != nil {
+		return fmt.Errorf("writing rerun script: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildRerunCommands(t *testing.T) {
+	t.Parallel()
+	results := map[string]*TestResult{
+		"github.com/Sixeight/gotestshow/example/TestMultiply": {
+			Package: "github.com/Sixeight/gotestshow/example",
+			Test:    "TestMultiply",
+			Failed:  true,
+		},
+		"github.com/Sixeight/gotestshow/example/TestDivide": {
+			Package:    "github.com/Sixeight/gotestshow/example",
+			Test:       "TestDivide",
+			Failed:     true,
+			HasSubtest: true,
+		},
+		"github.com/Sixeight/gotestshow/example/TestDivide/divide_by_zero": {
+			Package: "github.com/Sixeight/gotestshow/example",
+			Test:    "TestDivide/divide_by_zero",
+			Failed:  true,
+		},
+		"github.com/Sixeight/gotestshow/example/TestAdd": {
+			Package: "github.com/Sixeight/gotestshow/example",
+			Test:    "TestAdd",
+			Passed:  true,
+		},
+		"github.com/Sixeight/gotestshow/example/broken/[BUILD]": {
+			Package: "github.com/Sixeight/gotestshow/example/broken",
+			Test:    "[BUILD]",
+			Failed:  true,
+		},
+	}
+
+	commands := buildRerunCommands(results)
+	if len(commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d: %+v", len(commands), commands)
+	}
+
+	expected := []string{
+		"go test -run '^TestMultiply$' github.com/Sixeight/gotestshow/example",
+		"go test -run '^TestDivide$/^divide_by_zero$' github.com/Sixeight/gotestshow/example",
+	}
+	for i, want := range expected {
+		if commands[i].Command != want {
+			t.Errorf("commands[%d] = %q, want %q", i, commands[i].Command, want)
+		}
+	}
+}
+
+func TestBuildRerunCommands_ParentFailedOnItsOwn(t *testing.T) {
+	t.Parallel()
+	results := map[string]*TestResult{
+		"example/TestSetup":       {Package: "example", Test: "TestSetup", Failed: true, HasSubtest: true},
+		"example/TestSetup/first": {Package: "example", Test: "TestSetup/first", Passed: true},
+		"example/TestNested":      {Package: "example", Test: "TestNested", Failed: true, HasSubtest: true},
+		"example/TestNested/a":    {Package: "example", Test: "TestNested/a", Failed: true, HasSubtest: true},
+		"example/TestNested/a/b":  {Package: "example", Test: "TestNested/a/b", Failed: true},
+	}
+
+	var got []string
+	for _, command := range buildRerunCommands(results) {
+		got = append(got, command.Command)
+	}
+	expected := []string{
+		"go test -run '^TestSetup$' example",
+		"go test -run '^TestNested$/^a$/^b$' example",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestRunner_RerunScript(t *testing.T) {
+	t.Parallel()
+	quarantine, err := ParseQuarantine([]byte(`{"quarantine": [{"test": "TestFlaky"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(input string) string {
+		path := filepath.Join(t.TempDir(), "rerun.sh")
+		var output strings.Builder
+		runner := NewRunner(NewEventProcessor(), NewMockDisplay(), strings.NewReader(input), &output)
+		runner.SetConfig(&Config{RerunScript: path, Quarantine: quarantine})
+		runner.Run()
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	script := run(`{"Action":"run","Package":"example","Test":"TestFlaky"}
+{"Action":"fail","Package":"example","Test":"TestFlaky"}
+{"Action":"run","Package":"example","Test":"TestBroken"}
+{"Action":"fail","Package":"example","Test":"TestBroken"}
+{"Action":"fail","Package":"example"}
+`)
+	if !strings.Contains(script, "'^TestBroken$'") || strings.Contains(script, "TestFlaky") {
+		t.Errorf("expected only the failure that isn't quarantined to be rerun, got:\n%s", script)
+	}
+
+	if script := run(`{"Action":"run","Package":"example","Test":"TestFlaky"}
+{"Action":"fail","Package":"example","Test":"TestFlaky"}
+{"Action":"fail","Package":"example"}
+`); strings.Contains(script, "go test") || !strings.Contains(script, "# No tests failed") {
+		t.Errorf("expected a script without commands when only quarantined tests failed, got:\n%s", script)
+	}
+}
+
+func TestBuildRunPattern(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		parent   string
+		leaves   []string
+		expected string
+	}{
+		{
+			name:     "single top-level test",
+			leaves:   []string{"TestAdd"},
+			expected: "^TestAdd$",
+		},
+		{
+			name:     "multiple top-level tests",
+			leaves:   []string{"TestAdd", "TestSub"},
+			expected: "^(TestAdd|TestSub)$",
+		},
+		{
+			name:     "subtest with special characters",
+			parent:   "TestParse",
+			leaves:   []string{"a+b_(c)"},
+			expected: `^TestParse$/^a\+b_\(c\)$`,
+		},
+		{
+			name:     "nested subtests",
+			parent:   "TestA/level_1",
+			leaves:   []string{"x", "y"},
+			expected: "^TestA$/^level_1$/^(x|y)$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := buildRunPattern(tt.parent, tt.leaves); got != tt.expected {
+				t.Errorf("buildRunPattern(%q, %v) = %q, want %q", tt.parent, tt.leaves, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+	if got := shellQuote("^it's$"); got != `'^it'\''s$'` {
+		t.Errorf("shellQuote() = %q", got)
+	}
+}
+
+func TestWriteRerunScript(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rerun.sh")
+	commands := []rerunCommand{{Command: "go test -run '^TestA$' ./example"}}
+
+	if err := writeRerunScript(path, commands); err != nil {
+		t.Fatalf("writeRerunScript() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "#!/bin/sh\n") {
+		t.Error("Script should start with a shebang")
+	}
+	if !strings.Contains(string(content), "go test -run '^TestA$' ./example\n") {
+		t.Error("Script should contain the rerun command")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Error("Script should be executable")
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_RerunCommands(t *testing.T) {
+	t.Parallel()
+	for _, ci := range []bool{false, true} {
+		var buf strings.Builder
+		display := NewTerminalDisplay(&buf, false)
+		display.SetConfig(&Config{CIMode: ci})
+
+		packages := map[string]*PackageState{
+			"example": {Name: "example", Total: 1, Failed: 1},
+		}
+		results := map[string]*TestResult{
+			"example/TestMultiply": {Package: "example", Test: "TestMultiply", Failed: true},
+		}
+
+		display.ShowFinalResults(packages, results, nil, time.Now())
+
+		if !strings.Contains(buf.String(), "go test -run '^TestMultiply$' example") {
+			t.Errorf("CI=%v: output should contain the rerun command, got:\n%s", ci, buf.String())
+		}
+	}
+}
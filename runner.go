@@ -22,6 +22,9 @@ type Runner struct {
 	input       io.Reader
 	output      io.Writer
 	config      *Config
+	trace       *TraceRecorder // Records event timings when a trace file is requested
+	tee         *JSONTee       // Copies the input stream when a JSON file is requested
+	stopSignals func()         // Stops the signal handling set up for the run
 	interrupted bool
 	interruptMu sync.RWMutex
 }
@@ -39,12 +42,25 @@ func NewRunner(processor EventProcessor, display Display, input io.Reader, outpu
 // SetConfig sets the configuration for the runner
 func (r *Runner) SetConfig(config *Config) {
 	r.config = config
+	if config != nil && config.TraceFile != "" {
+		r.trace = NewTraceRecorder()
+	}
 }
 
 // Run executes the main application logic
 func (r *Runner) Run() int {
 	startTime := time.Now()
 
+	if r.config != nil && r.config.JSONFile != "" {
+		tee, err := OpenJSONTee(r.config.JSONFile, r.config.JSONFileEnriched, r.config.Quarantine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		r.tee = tee
+		defer r.closeTee()
+	}
+
 	ctx := r.setupEnvironment()
 	defer r.cleanup()
 
@@ -71,6 +87,9 @@ func (r *Runner) Run() int {
 		return 1
 	}
 
+	// Stop the progress line before the results are shown
+	cancel()
+
 	// Check if interrupted after processing
 	r.interruptMu.RLock()
 	wasInterrupted := r.interrupted
@@ -78,6 +97,9 @@ func (r *Runner) Run() int {
 
 	if wasInterrupted {
 		fmt.Fprintln(r.output, "\n\nInterrupted by user (Ctrl-C)")
+	} else {
+		// The input ended on its own: anything still running was cut off
+		r.processor.Finalize()
 	}
 
 	return r.showResults(startTime)
@@ -97,17 +119,29 @@ func (r *Runner) setupEnvironment() context.Context {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
-		<-sigChan
-		r.interruptMu.Lock()
-		r.interrupted = true
-		r.interruptMu.Unlock()
-		cancel()
+		select {
+		case <-sigChan:
+			r.interruptMu.Lock()
+			r.interrupted = true
+			r.interruptMu.Unlock()
+			cancel()
+		case <-ctx.Done():
+		}
 	}()
 
+	// Runs may follow each other in watch mode, so release the handler
+	r.stopSignals = func() {
+		signal.Stop(sigChan)
+		cancel()
+	}
+
 	return ctx
 }
 
 func (r *Runner) cleanup() {
+	if r.stopSignals != nil {
+		r.stopSignals()
+	}
 	if r.config == nil || !r.config.CIMode {
 		fmt.Fprint(r.output, "\033[?25h")
 	}
@@ -141,6 +175,7 @@ func (r *Runner) processInput() error {
 		var event TestEvent
 		if err := json.Unmarshal(line, &event); err != nil {
 			jsonErrorCount++
+			r.teeLine(line, nil)
 
 			if totalLines == 1 && len(line) > 0 && !validJSONFound && !bytes.Contains(line, []byte("{")) {
 				return fmt.Errorf("not JSON input")
@@ -150,6 +185,10 @@ func (r *Runner) processInput() error {
 
 		validJSONFound = true
 		r.processor.ProcessEvent(event)
+		if r.trace != nil {
+			r.trace.Record(event)
+		}
+		r.teeLine(line, &event)
 		r.displayEventResult(event)
 	}
 
@@ -160,10 +199,38 @@ func (r *Runner) processInput() error {
 	return nil
 }
 
+// teeLine copies a line to the JSON file. After a write error the copy is
+// abandoned so the error is reported only once.
+func (r *Runner) teeLine(line []byte, event *TestEvent) {
+	if r.tee == nil {
+		return
+	}
+	if err := r.tee.Write(line, event, r.processor.GetResults); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		r.closeTee()
+	}
+}
+
+func (r *Runner) closeTee() {
+	if r.tee == nil {
+		return
+	}
+	if err := r.tee.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	r.tee = nil
+}
+
 func (r *Runner) displayEventResult(event TestEvent) {
+	if r.config != nil && !r.config.Filter.Shows(eventTarget(event)) {
+		return
+	}
+
 	switch {
 	case event.Test != "" && (event.Action == "pass" || event.Action == "fail" || event.Action == "skip"):
 		r.displayTestResult(event)
+	case event.Test != "" && event.Action == "output" && r.config != nil && r.config.ShowOutput != nil:
+		r.displayTestOutput(event)
 	case event.Action == "build-fail":
 		r.displayBuildFailure(event)
 	case event.Package != "" && event.Action == "fail":
@@ -171,6 +238,20 @@ func (r *Runner) displayEventResult(event TestEvent) {
 	}
 }
 
+// eventTarget returns the package and test an event is displayed for, with
+// build and package failures as the tests "[BUILD]" and "[PACKAGE]"
+func eventTarget(event TestEvent) (string, string) {
+	switch {
+	case event.Action == "build-fail" || event.Action == "build-output":
+		packageName, _, _ := strings.Cut(event.ImportPath, " [")
+		return packageName, "[BUILD]"
+	case event.Test == "":
+		return event.Package, "[PACKAGE]"
+	default:
+		return event.Package, event.Test
+	}
+}
+
 func (r *Runner) displayTestResult(event TestEvent) {
 	results := r.processor.GetResults()
 	key := fmt.Sprintf("%s/%s", event.Package, event.Test)
@@ -179,6 +260,13 @@ func (r *Runner) displayTestResult(event TestEvent) {
 	}
 }
 
+func (r *Runner) displayTestOutput(event TestEvent) {
+	results := r.processor.GetResults()
+	if result, exists := results[fmt.Sprintf("%s/%s", event.Package, event.Test)]; exists {
+		r.display.ShowTestOutput(result, event.Output)
+	}
+}
+
 func (r *Runner) displayBuildFailure(event TestEvent) {
 	packageName := event.ImportPath
 	if idx := strings.Index(packageName, " ["); idx != -1 {
@@ -214,5 +302,98 @@ func (r *Runner) showResults(startTime time.Time) int {
 	r.display.ClearLine()
 	packages := r.processor.GetPackages()
 	results := r.processor.GetResults()
-	return r.display.ShowFinalResults(packages, results, startTime)
+	benchmarks := r.processor.GetBenchmarks()
+	var deltas []benchmarkDelta
+	if r.config != nil && r.config.BenchBaseline != nil {
+		deltas = compareBenchmarks(r.config.BenchBaseline, benchmarks, r.config.BenchRegression)
+	}
+	r.display.ShowBenchmarkResults(benchmarks, deltas)
+	exitCode := r.display.ShowFinalResults(packages, results, deltas, startTime)
+
+	// Partial timings of an interrupted or truncated run would skew the history
+	r.interruptMu.RLock()
+	wasInterrupted := r.interrupted
+	r.interruptMu.RUnlock()
+	if r.config != nil && r.config.History != nil && r.config.HistoryFile != "" && !wasInterrupted && !hasIncomplete(packages) {
+		r.config.History.Record(results)
+		if err := r.config.History.Save(r.config.HistoryFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if r.config != nil && r.config.BenchSave != "" && len(benchmarks) > 0 {
+		if err := SaveBenchmarks(r.config.BenchSave, benchmarks); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	// Like the summary, the reports, TAP output and rerun script leave quarantined failures out
+	var quarantine quarantineReport
+	if r.config != nil {
+		quarantine = applyQuarantine(r.config.Quarantine, packages, results, time.Now())
+	}
+
+	if r.config != nil && (r.config.HTMLReport != "" || r.config.MarkdownFile != "" || r.config.StepSummaryFile != "") {
+		r.writeReports(buildRunReport(quarantine, r.config, time.Since(startTime), exitCode), packages, results)
+	}
+
+	if r.config != nil && r.config.TAPFile != "" {
+		if err := WriteTAPFile(r.config.TAPFile, quarantine, r.config.TAPVersion); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if r.trace != nil {
+		if err := WriteTraceFile(r.config.TraceFile, r.trace); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if r.config != nil && r.config.RerunScript != "" {
+		if err := writeRerunScript(r.config.RerunScript, buildRerunCommands(quarantine.results)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if r.config != nil && r.config.TUI {
+		if err := runTUI(newTUIModel(packages, results, r.config)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	return exitCode
+}
+
+// hasIncomplete reports whether the input ended before any package completed
+func hasIncomplete(packages map[string]*PackageState) bool {
+	for _, pkg := range packages {
+		if pkg.Incomplete {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReports writes the HTML report and Markdown summaries that are enabled
+func (r *Runner) writeReports(report runReport, packages map[string]*PackageState, results map[string]*TestResult) {
+	if r.config.HTMLReport != "" {
+		if err := WriteHTMLReport(r.config.HTMLReport, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if r.config.MarkdownFile == "" && r.config.StepSummaryFile == "" {
+		return
+	}
+	summary := renderMarkdown(report, buildTopReport(packages, results, markdownSlowestTests).Tests)
+	if r.config.MarkdownFile != "" {
+		if err := WriteMarkdownSummary(r.config.MarkdownFile, summary, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+	if r.config.StepSummaryFile != "" {
+		if err := WriteMarkdownSummary(r.config.StepSummaryFile, summary, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
 }
@@ -78,6 +78,12 @@ func (m *MockEventProcessor) GetPackages() map[string]*PackageState {
 	return m.packages
 }
 
+func (m *MockEventProcessor) GetBenchmarks() []*BenchmarkResult {
+	return nil
+}
+
+func (m *MockEventProcessor) Finalize() {}
+
 func (m *MockEventProcessor) HasTestsStarted() bool {
 	return m.hasStarted
 }
@@ -87,6 +93,7 @@ type MockDisplay struct {
 	output          bytes.Buffer
 	progressCalls   int
 	testResults     []string
+	testOutput      []string
 	packageFailures []string
 	helpShown       bool
 	lineCleared     bool
@@ -104,11 +111,19 @@ func (m *MockDisplay) ShowTestResult(result *TestResult, success bool) {
 	m.testResults = append(m.testResults, result.Test)
 }
 
+func (m *MockDisplay) ShowTestOutput(result *TestResult, output string) {
+	m.testOutput = append(m.testOutput, output)
+}
+
 func (m *MockDisplay) ShowPackageFailure(packageName string, output []string) {
 	m.packageFailures = append(m.packageFailures, packageName)
 }
 
-func (m *MockDisplay) ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, startTime time.Time) int {
+func (m *MockDisplay) ShowBenchmarkResults(benchmarks []*BenchmarkResult, deltas []benchmarkDelta) {
+	// Mock implementation - no operation needed
+}
+
+func (m *MockDisplay) ShowFinalResults(packages map[string]*PackageState, results map[string]*TestResult, deltas []benchmarkDelta, startTime time.Time) int {
 	m.output.WriteString("Final results shown\n")
 	return 0
 }
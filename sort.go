@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortOrder is the order packages and tests are listed in the summary
+type SortOrder string
+
+const (
+	SortByName     SortOrder = "name"     // Alphabetically
+	SortByDuration SortOrder = "duration" // Slowest first
+	SortByStatus   SortOrder = "status"   // Failures first, then incomplete, skipped and passed
+)
+
+// parseSortOrder validates the value of -sort
+func parseSortOrder(value string) (SortOrder, error) {
+	switch order := SortOrder(value); order {
+	case SortByName, SortByDuration, SortByStatus:
+		return order, nil
+	}
+	return "", fmt.Errorf("invalid -sort %q: must be name, duration or status", value)
+}
+
+// statusRank orders the statuses of resultStatus and packageStatus for SortByStatus
+var statusRank = map[string]int{"fail": 0, "incomplete": 1, "running": 1, "skip": 2, "pass": 3}
+
+// sortPackageNames returns the package names in the given order. Ties, and
+// every package when sorting by name, are ordered alphabetically.
+func sortPackageNames(packages map[string]*PackageState, results map[string]*TestResult, order SortOrder) []string {
+	names := sortedPackageNames(packages)
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := packages[names[i]], packages[names[j]]
+		switch order {
+		case SortByDuration:
+			return a.Elapsed > b.Elapsed
+		case SortByStatus:
+			return statusRank[packageStatus(a, results)] < statusRank[packageStatus(b, results)]
+		}
+		return false
+	})
+	return names
+}
+
+// sortTestResults sorts tests in the given order, with ties ordered by
+// package and test name
+func sortTestResults(tests []*TestResult, order SortOrder) {
+	sort.SliceStable(tests, func(i, j int) bool {
+		a, b := tests[i], tests[j]
+		switch order {
+		case SortByDuration:
+			if a.Elapsed != b.Elapsed {
+				return a.Elapsed > b.Elapsed
+			}
+		case SortByStatus:
+			if rankA, rankB := statusRank[resultStatus(a)], statusRank[resultStatus(b)]; rankA != rankB {
+				return rankA < rankB
+			}
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Test < b.Test
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"name", "duration", "status"} {
+		if order, err := parseSortOrder(value); err != nil || string(order) != value {
+			t.Errorf("parseSortOrder(%q) = %q, %v", value, order, err)
+		}
+	}
+	if _, err := parseSortOrder("random"); err == nil {
+		t.Error("expected an error for an unknown sort order")
+	}
+}
+
+func TestSortPackageNames(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"c": {Name: "c", Total: 1, Passed: 1, Elapsed: 3},
+		"a": {Name: "a", Total: 1, Passed: 1, Elapsed: 1},
+		"b": {Name: "b", Total: 1, Failed: 1, Elapsed: 2},
+		"d": {Name: "d", Total: 1, Failed: 1, Elapsed: 2},
+	}
+
+	for order, expected := range map[SortOrder][]string{
+		SortByName:     {"a", "b", "c", "d"},
+		SortByDuration: {"c", "b", "d", "a"},
+		SortByStatus:   {"b", "d", "a", "c"},
+	} {
+		if got := sortPackageNames(packages, nil, order); !reflect.DeepEqual(got, expected) {
+			t.Errorf("sortPackageNames(%s) = %v, want %v", order, got, expected)
+		}
+	}
+}
+
+func TestSortTestResults(t *testing.T) {
+	t.Parallel()
+	results := []*TestResult{
+		{Package: "p", Test: "TestC", Passed: true, Elapsed: 0.1},
+		{Package: "p", Test: "TestA", Skipped: true, Elapsed: 0.3},
+		{Package: "p", Test: "TestB", Failed: true, Elapsed: 0.2},
+		{Package: "p", Test: "TestD", Failed: true, Elapsed: 0.3},
+	}
+
+	for order, expected := range map[SortOrder][]string{
+		SortByName:     {"TestA", "TestB", "TestC", "TestD"},
+		SortByDuration: {"TestA", "TestD", "TestB", "TestC"},
+		SortByStatus:   {"TestB", "TestD", "TestA", "TestC"},
+	} {
+		sortTestResults(results, order)
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Test)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("sortTestResults(%s) = %v, want %v", order, got, expected)
+		}
+	}
+}
+
+func TestTerminalDisplay_ShowFinalResults_Sort(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"example/fast": {Name: "example/fast", Total: 1, Failed: 1, IndividualTestFailed: 1, Elapsed: 0.1, Completed: true},
+		"example/slow": {Name: "example/slow", Total: 2, Failed: 2, IndividualTestFailed: 2, Elapsed: 5, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/fast/TestA": {Package: "example/fast", Test: "TestA", Failed: true, Elapsed: 0.1},
+		"example/slow/TestA": {Package: "example/slow", Test: "TestA", Failed: true, Elapsed: 1},
+		"example/slow/TestB": {Package: "example/slow", Test: "TestB", Failed: true, Elapsed: 4},
+	}
+
+	run := func(order SortOrder) string {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, true)
+		display.SetConfig(&Config{CIMode: true, Sort: order})
+		display.ShowFinalResults(packages, results, nil, time.Now())
+		return buf.String()
+	}
+
+	// The output is the same on every run, in the requested order
+	byName := run(SortByName)
+	for i := 0; i < 5; i++ {
+		if again := run(SortByName); again != byName {
+			t.Fatalf("summary changed between runs:\n%s\n---\n%s", byName, again)
+		}
+	}
+	if !inOrder(byName, "FAIL example/fast", "FAIL example/slow", "FAIL TestA (1", "FAIL TestB") {
+		t.Errorf("expected packages and tests by name.\nGot:\n%s", byName)
+	}
+	if byDuration := run(SortByDuration); !inOrder(byDuration, "FAIL example/slow", "FAIL TestB", "FAIL TestA (1", "FAIL example/fast") {
+		t.Errorf("expected the slowest packages and tests first.\nGot:\n%s", byDuration)
+	}
+}
+
+// inOrder reports whether the substrings occur in s one after another
+func inOrder(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		idx := strings.Index(s, sub)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(sub):]
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tapTest is one "ok"/"not ok" line of a TAP stream
+type tapTest struct {
+	result     *TestResult
+	name       string
+	quarantine *QuarantineEntry // Entry covering the failure of a quarantined test
+}
+
+// writeTAP writes the results in the Test Anything Protocol. Version 14 puts
+// every package into a subtest with its own plan; version 13 has no
+// subtests, so tests are listed in one flat plan with package-qualified names.
+// Quarantined failures are "not ok" with a TODO directive and don't fail their package.
+func writeTAP(w io.Writer, run quarantineReport, version int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "TAP version %d\n", version)
+
+	packages, results := run.packages, run.results
+	names, tests := tapTestsByPackage(run)
+
+	if version < 14 {
+		var all []tapTest
+		for _, name := range names {
+			for _, test := range tests[name] {
+				test.name = name + " " + test.name
+				all = append(all, test)
+			}
+		}
+		fmt.Fprintf(bw, "1..%d\n", len(all))
+		for i, test := range all {
+			writeTAPTest(bw, "", i+1, test)
+		}
+		return bw.Flush()
+	}
+
+	fmt.Fprintf(bw, "1..%d\n", len(names))
+	for i, name := range names {
+		fmt.Fprintf(bw, "# Subtest: %s\n", name)
+		fmt.Fprintf(bw, "    1..%d\n", len(tests[name]))
+		failed := false
+		for j, test := range tests[name] {
+			writeTAPTest(bw, "    ", j+1, test)
+			failed = failed || (!tapOK(test.result) && test.quarantine == nil)
+		}
+
+		status := "fail"
+		if pkg, exists := packages[name]; exists {
+			status = packageStatus(pkg, results)
+		}
+		switch {
+		case failed || status == "fail" || status == "incomplete":
+			fmt.Fprintf(bw, "not ok %d - %s\n", i+1, tapEscape(name))
+		case status == "skip":
+			fmt.Fprintf(bw, "ok %d - %s # SKIP no tests\n", i+1, tapEscape(name))
+		default:
+			fmt.Fprintf(bw, "ok %d - %s\n", i+1, tapEscape(name))
+		}
+	}
+	return bw.Flush()
+}
+
+// tapTestsByPackage returns the sorted package names and the leaf tests and
+// build or package failures of each package. Quarantined tests keep their
+// failed result.
+func tapTestsByPackage(run quarantineReport) ([]string, map[string][]tapTest) {
+	packages := run.packages
+	quarantined := make(map[string]quarantinedTest, len(run.quarantined))
+	for _, test := range run.quarantined {
+		quarantined[test.result.Package+"/"+test.result.Test] = test
+	}
+
+	tests := make(map[string][]tapTest)
+	for name := range packages {
+		tests[name] = nil
+	}
+
+	for _, result := range sortedResults(run.results) {
+		if result.HasSubtest {
+			continue
+		}
+		if result.Test == "[PACKAGE]" {
+			if pkg, exists := packages[result.Package]; exists && !shouldDisplayPackageFailure(pkg) {
+				continue
+			}
+		}
+		test := tapTest{result: result, name: result.Test}
+		if q, exists := quarantined[result.Package+"/"+result.Test]; exists {
+			test.result, test.quarantine = q.result, q.entry
+		}
+		tests[result.Package] = append(tests[result.Package], test)
+	}
+
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, tests
+}
+
+// tapOK reports whether the result is reported as "ok": tests that failed,
+// were cut off or never finished are "not ok"
+func tapOK(result *TestResult) bool {
+	status := resultStatus(result)
+	return status == "pass" || status == "skip"
+}
+
+// writeTAPTest writes the test line and, for failures, a YAML diagnostic block
+func writeTAPTest(w io.Writer, indent string, number int, test tapTest) {
+	result := test.result
+	status, directive := "ok", ""
+	switch {
+	case test.quarantine != nil:
+		status, directive = "not ok", " # TODO quarantined"
+		if test.quarantine.Reason != "" {
+			directive += ": " + tapEscape(test.quarantine.Reason)
+		}
+	case !tapOK(result):
+		status = "not ok"
+	case result.Skipped:
+		directive = " # SKIP"
+		if reason := skipReason(result.Output); reason != "" {
+			directive += " " + tapEscape(reason)
+		}
+	}
+	fmt.Fprintf(w, "%s%s %d - %s%s\n", indent, status, number, tapEscape(test.name), directive)
+
+	if tapOK(result) {
+		return
+	}
+
+	message := "test failed"
+	if !result.Failed {
+		message = "test did not finish"
+	}
+	fmt.Fprintf(w, "%s  ---\n", indent)
+	fmt.Fprintf(w, "%s  message: %s\n", indent, strconv.Quote(message))
+	fmt.Fprintf(w, "%s  severity: fail\n", indent)
+	if result.Location != "" {
+		fmt.Fprintf(w, "%s  at: %s\n", indent, strconv.Quote(result.Location))
+	}
+	fmt.Fprintf(w, "%s  duration_ms: %.0f\n", indent, result.Elapsed*1000)
+	if output := extractRelevantOutput(result.Output); len(output) > 0 {
+		fmt.Fprintf(w, "%s  output: |\n", indent)
+		for _, line := range output {
+			fmt.Fprintf(w, "%s    %s\n", indent, strings.TrimRight(line, "\r\n"))
+		}
+	}
+	fmt.Fprintf(w, "%s  ...\n", indent)
+}
+
+// skipReason returns the message logged by t.Skip, if any
+func skipReason(output []string) string {
+	for _, line := range extractRelevantOutput(output) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "--- SKIP") {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// tapEscape escapes the characters with a special meaning in TAP descriptions
+func tapEscape(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	return strings.ReplaceAll(text, "#", "\\#")
+}
+
+// WriteTAPFile writes the TAP stream to path, or to stdout if path is "-"
+func WriteTAPFile(path string, run quarantineReport, version int) error {
+	if path == "-" {
+		return writeTAP(os.Stdout, run, version)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing TAP output: %w", err)
+	}
+	if err := writeTAP(file, run, version); err != nil {
+		file.Close()
+		return fmt.Errorf("writing TAP output: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing TAP output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func tapFixture() (map[string]*PackageState, map[string]*TestResult) {
+	packages := map[string]*PackageState{
+		"example/a": {Name: "example/a", Total: 4, Passed: 1, Failed: 1, Skipped: 1, Completed: true},
+		"example/b": {Name: "example/b", Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/a/TestPass": {Package: "example/a", Test: "TestPass", Passed: true, Elapsed: 0.1},
+		"example/a/TestFail": {Package: "example/a", Test: "TestFail", Failed: true, Elapsed: 0.25, Location: "a_test.go:12", HasSubtest: true},
+		"example/a/TestFail/case_#1": {
+			Package: "example/a", Test: "TestFail/case_#1", Failed: true, Elapsed: 0.25, Location: "a_test.go:12",
+			Output: []string{"=== RUN   TestFail/case_#1\n", "    a_test.go:12: got 1, want 2\n", "    --- FAIL: TestFail/case_#1 (0.25s)\n"},
+		},
+		"example/a/TestSkip": {
+			Package: "example/a", Test: "TestSkip", Skipped: true,
+			Output: []string{"=== RUN   TestSkip\n", "    a_test.go:20: needs network\n", "--- SKIP: TestSkip (0.00s)\n"},
+		},
+	}
+	return packages, results
+}
+
+func TestWriteTAP_Version14(t *testing.T) {
+	t.Parallel()
+	packages, results := tapFixture()
+
+	var out strings.Builder
+	if err := writeTAP(&out, applyQuarantine(nil, packages, results, time.Now()), 14); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `TAP version 14
+1..2
+# Subtest: example/a
+    1..3
+    not ok 1 - TestFail/case_\#1
+      ---
+      message: "test failed"
+      severity: fail
+      at: "a_test.go:12"
+      duration_ms: 250
+      output: |
+            a_test.go:12: got 1, want 2
+            --- FAIL: TestFail/case_#1 (0.25s)
+      ...
+    ok 2 - TestPass
+    ok 3 - TestSkip # SKIP a_test.go:20: needs network
+not ok 1 - example/a
+# Subtest: example/b
+    1..0
+ok 2 - example/b # SKIP no tests
+`
+	if out.String() != expected {
+		t.Errorf("unexpected TAP output.\nGot:\n%s\nExpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestWriteTAP_Version13(t *testing.T) {
+	t.Parallel()
+	packages, results := tapFixture()
+	packages["example/c"] = &PackageState{Name: "example/c", Total: 1, Running: 1, Incomplete: true}
+	results["example/c/TestHang"] = &TestResult{Package: "example/c", Test: "TestHang", Started: true, Incomplete: true}
+
+	var out strings.Builder
+	if err := writeTAP(&out, applyQuarantine(nil, packages, results, time.Now()), 13); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"TAP version 13\n1..4\n",
+		"not ok 1 - example/a TestFail/case_\\#1\n",
+		"ok 2 - example/a TestPass\n",
+		"ok 3 - example/a TestSkip # SKIP",
+		"not ok 4 - example/c TestHang\n  ---\n  message: \"test did not finish\"\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected TAP output to contain %q.\nGot:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "# Subtest") {
+		t.Error("TAP version 13 should not contain subtests")
+	}
+}
+
+func TestWriteTAP_BuildFailure(t *testing.T) {
+	t.Parallel()
+	results := map[string]*TestResult{
+		"example/broken/[BUILD]": {
+			Package: "example/broken", Test: "[BUILD]", Failed: true,
+			Output: []string{"broken.go:6:9: undefined: undefinedVariable\n"},
+		},
+	}
+
+	var out strings.Builder
+	if err := writeTAP(&out, applyQuarantine(nil, map[string]*PackageState{}, results, time.Now()), 14); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# Subtest: example/broken\n    1..1\n    not ok 1 - [BUILD]\n",
+		"        broken.go:6:9: undefined: undefinedVariable\n",
+		"not ok 1 - example/broken\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected TAP output to contain %q.\nGot:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestWriteTAP_Quarantine(t *testing.T) {
+	t.Parallel()
+	packages, results := tapFixture()
+	q := mustParseQuarantine(t, `{"quarantine":[{"test":"TestFail","reason":"TICKET-1"}]}`)
+
+	var out strings.Builder
+	if err := writeTAP(&out, applyQuarantine(q, packages, results, time.Now()), 14); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"    not ok 1 - TestFail/case_\\#1 # TODO quarantined: TICKET-1\n      ---\n      message: \"test failed\"\n",
+		"ok 1 - example/a\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected TAP output to contain %q.\nGot:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// jsonTeeMaxOutput is the number of bytes of an output event kept in the enriched stream
+const jsonTeeMaxOutput = 4096
+
+// JSONTee copies the received go test -json stream to a file so that other
+// tools can consume it. In raw mode every line is copied unchanged. In
+// enriched mode only valid events are copied, annotated with what gotestshow
+// knows about them:
+//
+//   - Location: the file and line of a failure, on pass/fail/skip and build-fail events
+//   - Quarantined, QuarantineReason: the test is covered by an active quarantine entry
+//   - Flaky: the test passed after failing earlier in the same stream
+//   - OutputTruncated: the size of an output that was cut to jsonTeeMaxOutput bytes
+type JSONTee struct {
+	w          io.Writer
+	closer     io.Closer // Nil when writing to stdout
+	enriched   bool
+	quarantine *Quarantine
+	now        time.Time
+	failed     map[string]bool // Tests that failed earlier in the stream
+}
+
+// OpenJSONTee opens path for writing ("-" for stdout) and returns a tee writing to it
+func OpenJSONTee(path string, enriched bool, quarantine *Quarantine) (*JSONTee, error) {
+	if path == "-" {
+		return NewJSONTee(os.Stdout, enriched, quarantine), nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening JSON file: %w", err)
+	}
+	tee := NewJSONTee(file, enriched, quarantine)
+	tee.closer = file
+	return tee, nil
+}
+
+// NewJSONTee creates a tee writing to w
+func NewJSONTee(w io.Writer, enriched bool, quarantine *Quarantine) *JSONTee {
+	return &JSONTee{
+		w:          w,
+		enriched:   enriched,
+		quarantine: quarantine,
+		now:        time.Now(),
+		failed:     make(map[string]bool),
+	}
+}
+
+// Write copies a received line. The event is nil for lines that are not JSON;
+// the results returned by getResults must already include the event.
+func (t *JSONTee) Write(line []byte, event *TestEvent, getResults func() map[string]*TestResult) error {
+	if !t.enriched {
+		return t.writeLine(line)
+	}
+	if event == nil {
+		return nil
+	}
+
+	enriched, err := t.enrich(line, *event, getResults)
+	if err != nil {
+		return err
+	}
+	return t.writeLine(enriched)
+}
+
+func (t *JSONTee) writeLine(line []byte) error {
+	if _, err := t.w.Write(append(line[:len(line):len(line)], '\n')); err != nil {
+		return fmt.Errorf("writing JSON file: %w", err)
+	}
+	return nil
+}
+
+// enrich adds the annotations to the event. Fields of the original line are
+// kept, including ones gotestshow does not know about.
+func (t *JSONTee) enrich(line []byte, event TestEvent, getResults func() map[string]*TestResult) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, fmt.Errorf("enriching event: %w", err)
+	}
+	set := func(name string, value any) {
+		data, _ := json.Marshal(value)
+		fields[name] = data
+	}
+
+	if len(event.Output) > jsonTeeMaxOutput {
+		set("Output", truncateOutput(event.Output, jsonTeeMaxOutput))
+		set("OutputTruncated", len(event.Output))
+	}
+
+	switch {
+	case event.Action == "build-fail":
+		packageName, _, _ := strings.Cut(event.ImportPath, " [")
+		if result, exists := getResults()[packageName+"/[BUILD]"]; exists && result.Location != "" {
+			set("Location", result.Location)
+		}
+	case event.Test != "" && (event.Action == "pass" || event.Action == "fail" || event.Action == "skip"):
+		key := event.Package + "/" + event.Test
+		if result, exists := getResults()[key]; exists && result.Location != "" {
+			set("Location", result.Location)
+		}
+		if entry := t.quarantine.Match(event.Package, event.Test); entry != nil && !entry.IsExpired(t.now) {
+			set("Quarantined", true)
+			if entry.Reason != "" {
+				set("QuarantineReason", entry.Reason)
+			}
+		}
+		if event.Action == "fail" {
+			t.failed[key] = true
+		} else if event.Action == "pass" && t.failed[key] {
+			set("Flaky", true)
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("enriching event: %w", err)
+	}
+	return data, nil
+}
+
+// truncateOutput cuts output to at most limit bytes on a character boundary
+func truncateOutput(output string, limit int) string {
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut] + fmt.Sprintf("… (%d bytes truncated)\n", len(output)-cut)
+}
+
+// Close closes the file the tee writes to
+func (t *JSONTee) Close() error {
+	if t.closer == nil {
+		return nil
+	}
+	if err := t.closer.Close(); err != nil {
+		return fmt.Errorf("writing JSON file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONTee_Raw(t *testing.T) {
+	t.Parallel()
+	var out strings.Builder
+	tee := NewJSONTee(&out, false, nil)
+
+	lines := []string{
+		"# example",
+		`{"Action":"run","Package":"example","Test":"TestA"}`,
+		`{"Action":"pass","Package":"example","Test":"TestA","Elapsed":0.1}`,
+	}
+	event := &TestEvent{Action: "run", Package: "example", Test: "TestA"}
+	for i, line := range lines {
+		var lineEvent *TestEvent
+		if i > 0 {
+			lineEvent = event
+		}
+		if err := tee.Write([]byte(line), lineEvent, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := strings.Join(lines, "\n") + "\n"; out.String() != expected {
+		t.Errorf("expected the input to be copied unchanged.\nGot:\n%s", out.String())
+	}
+}
+
+func TestJSONTee_Enriched(t *testing.T) {
+	t.Parallel()
+	quarantine, err := ParseQuarantine([]byte(`{"quarantine": [{"test": "TestFlaky", "reason": "issue 42"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	tee := NewJSONTee(&out, true, quarantine)
+
+	results := map[string]*TestResult{
+		"example/TestFlaky":  {Package: "example", Test: "TestFlaky", Location: "flaky_test.go:9"},
+		"example/[BUILD]":    {Package: "example", Test: "[BUILD]", Location: "broken.go:6"},
+		"example/TestPassed": {Package: "example", Test: "TestPassed"},
+	}
+	getResults := func() map[string]*TestResult { return results }
+
+	longOutput := strings.Repeat("é", jsonTeeMaxOutput)
+	inputs := []string{
+		`not json`,
+		`{"Action":"fail","Package":"example","Test":"TestFlaky","Elapsed":0.1,"Custom":"kept"}`,
+		`{"Action":"pass","Package":"example","Test":"TestFlaky","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example","Test":"TestPassed","Elapsed":0.1}`,
+		`{"Action":"output","Package":"example","Test":"TestPassed","Output":"` + longOutput + `"}`,
+		`{"Action":"build-fail","ImportPath":"example [example.test]"}`,
+	}
+	for _, input := range inputs {
+		var event TestEvent
+		var eventPtr *TestEvent
+		if json.Unmarshal([]byte(input), &event) == nil {
+			eventPtr = &event
+		}
+		if err := tee.Write([]byte(input), eventPtr, getResults); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(inputs)-1 {
+		t.Fatalf("expected %d events without the non-JSON line, got %d:\n%s", len(inputs)-1, len(lines), out.String())
+	}
+	decoded := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &decoded[i]); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+	}
+
+	failed := decoded[0]
+	if failed["Location"] != "flaky_test.go:9" || failed["Quarantined"] != true || failed["QuarantineReason"] != "issue 42" || failed["Custom"] != "kept" {
+		t.Errorf("unexpected annotations of the failure: %v", failed)
+	}
+	if _, exists := failed["Flaky"]; exists {
+		t.Error("the first failure should not be flaky")
+	}
+	if decoded[1]["Flaky"] != true {
+		t.Errorf("expected the pass after a failure to be flaky: %v", decoded[1])
+	}
+	if _, exists := decoded[2]["Quarantined"]; exists {
+		t.Errorf("unexpected quarantine annotation: %v", decoded[2])
+	}
+
+	output := decoded[3]["Output"].(string)
+	if len(output) > jsonTeeMaxOutput+100 || !strings.HasSuffix(output, "bytes truncated)\n") {
+		t.Errorf("expected truncated output, got %d bytes ending with %q", len(output), output[len(output)-30:])
+	}
+	if decoded[3]["OutputTruncated"] != float64(len(longOutput)) {
+		t.Errorf("expected the original output size, got %v", decoded[3]["OutputTruncated"])
+	}
+
+	if decoded[4]["Location"] != "broken.go:6" {
+		t.Errorf("expected the build failure location, got %v", decoded[4])
+	}
+}
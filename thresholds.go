@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// ThresholdOverride sets the slow test threshold for matching packages or tests
+type ThresholdOverride struct {
+	Package   string `json:"package"`   // Regexp matched against the full package import path (empty matches all)
+	Test      string `json:"test"`      // Regexp matched against the test name or any of its parents (empty matches all)
+	Threshold string `json:"threshold"` // Duration (e.g., "5s"), "0" never reports matching tests as slow
+
+	packageRe *regexp.Regexp
+	testRe    *regexp.Regexp
+	duration  time.Duration
+}
+
+// ThresholdOverrides holds the per-package and per-test thresholds.
+// The first matching entry wins.
+type ThresholdOverrides struct {
+	Entries []*ThresholdOverride `json:"thresholds"`
+}
+
+// LoadThresholdOverrides reads a threshold override file in JSON format
+func LoadThresholdOverrides(path string) (*ThresholdOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading thresholds file: %w", err)
+	}
+	return ParseThresholdOverrides(data)
+}
+
+// ParseThresholdOverrides parses threshold override file content and compiles its patterns
+func ParseThresholdOverrides(data []byte) (*ThresholdOverrides, error) {
+	var o ThresholdOverrides
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("parsing thresholds file: %w", err)
+	}
+
+	for i, entry := range o.Entries {
+		if entry.Package == "" && entry.Test == "" {
+			return nil, fmt.Errorf("threshold entry %d: package or test pattern is required", i+1)
+		}
+
+		var err error
+		if entry.duration, err = time.ParseDuration(entry.Threshold); err != nil {
+			return nil, fmt.Errorf("threshold entry %d: invalid threshold: %w", i+1, err)
+		}
+		if entry.Package != "" {
+			if entry.packageRe, err = compileAnchored(entry.Package); err != nil {
+				return nil, fmt.Errorf("threshold entry %d: invalid package pattern: %w", i+1, err)
+			}
+		}
+		if entry.Test != "" {
+			if entry.testRe, err = compileAnchored(entry.Test); err != nil {
+				return nil, fmt.Errorf("threshold entry %d: invalid test pattern: %w", i+1, err)
+			}
+		}
+	}
+	return &o, nil
+}
+
+// Matches reports whether the entry applies to the given test.
+// A test matches if its own name or the name of any parent test matches.
+func (e *ThresholdOverride) Matches(packageName, testName string) bool {
+	return matchesTestOrParent(e.packageRe, e.testRe, packageName, testName)
+}
+
+// Match returns the first entry applying to the given test, or nil
+func (o *ThresholdOverrides) Match(packageName, testName string) *ThresholdOverride {
+	if o == nil {
+		return nil
+	}
+	for _, entry := range o.Entries {
+		if entry.Matches(packageName, testName) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// thresholdFor returns the slow test threshold for a test and whether it
+// comes from an override rather than the default
+func thresholdFor(overrides *ThresholdOverrides, defaultThreshold time.Duration, packageName, testName string) (time.Duration, bool) {
+	if entry := overrides.Match(packageName, testName); entry != nil {
+		return entry.duration, true
+	}
+	return defaultThreshold, false
+}
+
+// exceedsThreshold reports whether elapsed seconds are above a positive threshold
+func exceedsThreshold(elapsed float64, threshold time.Duration) bool {
+	return threshold > 0 && time.Duration(elapsed*float64(time.Second)) > threshold
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseThresholdOverrides(t *testing.T, content string) *ThresholdOverrides {
+	t.Helper()
+	o, err := ParseThresholdOverrides([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseThresholdOverrides() error = %v", err)
+	}
+	return o
+}
+
+func TestParseThresholdOverrides_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{`},
+		{"missing patterns", `{"thresholds":[{"threshold":"1s"}]}`},
+		{"invalid threshold", `{"thresholds":[{"package":"example","threshold":"slow"}]}`},
+		{"invalid test pattern", `{"thresholds":[{"test":"Test(","threshold":"1s"}]}`},
+		{"invalid package pattern", `{"thresholds":[{"package":"(","threshold":"1s"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseThresholdOverrides([]byte(tt.content)); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestThresholdFor(t *testing.T) {
+	t.Parallel()
+	overrides := mustParseThresholdOverrides(t, `{"thresholds":[
+		{"package":".*/integration","test":"TestMigrate","threshold":"30s"},
+		{"package":".*/integration","threshold":"5s"},
+		{"test":"TestQuick.*","threshold":"50ms"}
+	]}`)
+
+	tests := []struct {
+		pkg                string
+		test               string
+		expectedThreshold  time.Duration
+		expectedOverridden bool
+	}{
+		{"example.com/app/integration", "TestMigrate", 30 * time.Second, true},
+		{"example.com/app/integration", "TestMigrate/up", 30 * time.Second, true},
+		{"example.com/app/integration", "TestLogin", 5 * time.Second, true},
+		{"example.com/app/unit", "TestQuickSort", 50 * time.Millisecond, true},
+		{"example.com/app/unit", "TestParse", 500 * time.Millisecond, false},
+	}
+
+	for _, tt := range tests {
+		threshold, overridden := thresholdFor(overrides, 500*time.Millisecond, tt.pkg, tt.test)
+		if threshold != tt.expectedThreshold || overridden != tt.expectedOverridden {
+			t.Errorf("thresholdFor(%q, %q) = (%s, %v), want (%s, %v)",
+				tt.pkg, tt.test, threshold, overridden, tt.expectedThreshold, tt.expectedOverridden)
+		}
+	}
+
+	if threshold, overridden := thresholdFor(nil, time.Second, "example", "TestA"); threshold != time.Second || overridden {
+		t.Errorf("thresholdFor(nil) = (%s, %v), want (1s, false)", threshold, overridden)
+	}
+}
+
+func TestShowSlowTestsSummary_ThresholdOverrides(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	display := NewTerminalDisplay(&buf, false).(*TerminalDisplay)
+	display.SetConfig(&Config{
+		TimingMode: true,
+		Threshold:  500 * time.Millisecond,
+		Thresholds: mustParseThresholdOverrides(t, `{"thresholds":[
+			{"package":"integration","threshold":"5s"},
+			{"package":"unit","test":"TestTiny","threshold":"10ms"}
+		]}`),
+	})
+
+	results := map[string]*TestResult{
+		"integration/TestWithinOverride": {Package: "integration", Test: "TestWithinOverride", Passed: true, Elapsed: 2},
+		"integration/TestOverOverride":   {Package: "integration", Test: "TestOverOverride", Passed: true, Elapsed: 6},
+		"unit/TestTiny":                  {Package: "unit", Test: "TestTiny", Passed: true, Elapsed: 0.05},
+		"unit/TestDefault":               {Package: "unit", Test: "TestDefault", Passed: true, Elapsed: 0.8},
+	}
+
+	display.showSlowTestsSummary(results)
+	output := buf.String()
+
+	for _, expected := range []string{
+		"TestOverOverride",
+		"(6.000s > 5s)",
+		"TestTiny",
+		"(50ms > 10ms)",
+		"TestDefault",
+		"(800ms)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, but it didn't.\nGot: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "TestWithinOverride") {
+		t.Errorf("test within its overridden threshold should not be listed.\nGot: %s", output)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// rankedEntry is one line of the top-N report
+type rankedEntry struct {
+	Package  string
+	Name     string  // Test name, empty for packages
+	Elapsed  float64 // Seconds
+	Share    float64 // Percentage of the total of its category
+	Subtests int     // Number of leaf subtests for cumulative entries
+}
+
+// topReport lists the slowest tests, packages and test trees of a run
+type topReport struct {
+	Tests      []rankedEntry // Slowest individual (leaf) tests
+	Packages   []rankedEntry // Slowest packages
+	Cumulative []rankedEntry // Top-level tests with the most time summed across their subtests
+}
+
+// buildTopReport ranks the n slowest tests, packages and test trees
+func buildTopReport(packages map[string]*PackageState, results map[string]*TestResult, n int) topReport {
+	var report topReport
+	if n <= 0 {
+		return report
+	}
+
+	totalTestTime := 0.0
+	trees := make(map[string]*rankedEntry)
+	for _, result := range results {
+		if result.HasSubtest || isSyntheticResult(result) {
+			continue
+		}
+		totalTestTime += result.Elapsed
+		report.Tests = append(report.Tests, rankedEntry{Package: result.Package, Name: result.Test, Elapsed: result.Elapsed})
+
+		root, _, isSubtest := strings.Cut(result.Test, "/")
+		if !isSubtest {
+			continue
+		}
+		key := result.Package + "/" + root
+		tree, exists := trees[key]
+		if !exists {
+			tree = &rankedEntry{Package: result.Package, Name: root}
+			trees[key] = tree
+		}
+		tree.Elapsed += result.Elapsed
+		tree.Subtests++
+	}
+	for _, tree := range trees {
+		report.Cumulative = append(report.Cumulative, *tree)
+	}
+
+	totalPackageTime := 0.0
+	for name, pkg := range packages {
+		totalPackageTime += pkg.Elapsed
+		report.Packages = append(report.Packages, rankedEntry{Package: name, Elapsed: pkg.Elapsed})
+	}
+
+	report.Tests = rankEntries(report.Tests, totalTestTime, n)
+	report.Packages = rankEntries(report.Packages, totalPackageTime, n)
+	report.Cumulative = rankEntries(report.Cumulative, totalTestTime, n)
+	return report
+}
+
+// rankEntries sorts entries slowest first, keeps the first n and computes
+// their share of total
+func rankEntries(entries []rankedEntry, total float64, n int) []rankedEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Elapsed != entries[j].Elapsed {
+			return entries[i].Elapsed > entries[j].Elapsed
+		}
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	for i := range entries {
+		if total > 0 {
+			entries[i].Share = entries[i].Elapsed / total * 100
+		}
+	}
+	return entries
+}
+
+// isEmpty reports whether the report has nothing to show
+func (r topReport) isEmpty() bool {
+	return len(r.Tests) == 0 && len(r.Packages) == 0 && len(r.Cumulative) == 0
+}
+
+// topSection is a titled category of the top-N report
+type topSection struct {
+	title   string
+	entries []rankedEntry
+}
+
+// sections returns the categories of the report in display order
+func (r topReport) sections() []topSection {
+	return []topSection{
+		{"Slowest tests", r.Tests},
+		{"Slowest packages", r.Packages},
+		{"Most time across subtests", r.Cumulative},
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildTopReport(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"fast": {Name: "fast", Elapsed: 1},
+		"slow": {Name: "slow", Elapsed: 3},
+	}
+	results := map[string]*TestResult{
+		"slow/TestTree":       {Package: "slow", Test: "TestTree", Passed: true, Elapsed: 2, HasSubtest: true},
+		"slow/TestTree/a":     {Package: "slow", Test: "TestTree/a", Passed: true, Elapsed: 1.2},
+		"slow/TestTree/b":     {Package: "slow", Test: "TestTree/b", Passed: true, Elapsed: 0.8},
+		"slow/TestSingle":     {Package: "slow", Test: "TestSingle", Passed: true, Elapsed: 1.5},
+		"fast/TestQuick":      {Package: "fast", Test: "TestQuick", Passed: true, Elapsed: 0.5},
+		"fast/[PACKAGE]":      {Package: "fast", Test: "[PACKAGE]", Failed: true, Elapsed: 9},
+		"fast/TestOther/only": {Package: "fast", Test: "TestOther/only", Passed: true, Elapsed: 0},
+	}
+
+	report := buildTopReport(packages, results, 2)
+
+	assertRanked := func(name string, entries []rankedEntry, expected []string, shares []float64) {
+		t.Helper()
+		if len(entries) != len(expected) {
+			t.Fatalf("%s: got %d entries, want %d: %+v", name, len(entries), len(expected), entries)
+		}
+		for i, entry := range entries {
+			label := entry.Name
+			if label == "" {
+				label = entry.Package
+			}
+			if label != expected[i] {
+				t.Errorf("%s[%d] = %s, want %s", name, i, label, expected[i])
+			}
+			if math.Abs(entry.Share-shares[i]) > 0.01 {
+				t.Errorf("%s[%d] share = %.2f, want %.2f", name, i, entry.Share, shares[i])
+			}
+		}
+	}
+
+	// Leaf test time totals 4s
+	assertRanked("Tests", report.Tests, []string{"TestSingle", "TestTree/a"}, []float64{37.5, 30})
+	assertRanked("Packages", report.Packages, []string{"slow", "fast"}, []float64{75, 25})
+	assertRanked("Cumulative", report.Cumulative, []string{"TestTree", "TestOther"}, []float64{50, 0})
+
+	if report.Cumulative[0].Subtests != 2 {
+		t.Errorf("TestTree subtests = %d, want 2", report.Cumulative[0].Subtests)
+	}
+
+	if empty := buildTopReport(packages, results, 0); !empty.isEmpty() {
+		t.Error("Top report should be empty when disabled")
+	}
+}
+
+func TestTerminalDisplay_TopReport(t *testing.T) {
+	t.Parallel()
+	packages := map[string]*PackageState{
+		"example": {Name: "example", Total: 2, Passed: 2, Elapsed: 2, Completed: true},
+	}
+	results := map[string]*TestResult{
+		"example/TestSlow": {Package: "example", Test: "TestSlow", Passed: true, Elapsed: 1.5},
+		"example/TestFast": {Package: "example", Test: "TestFast", Passed: true, Elapsed: 0.5},
+	}
+
+	for _, ciMode := range []bool{false, true} {
+		var buf bytes.Buffer
+		display := NewTerminalDisplay(&buf, false)
+		display.SetConfig(&Config{CIMode: ciMode, Top: 1, Threshold: 500 * time.Millisecond})
+		display.ShowFinalResults(packages, results, nil, time.Now())
+
+		output := buf.String()
+		for _, expected := range []string{"Top 1", "Slowest tests", "1. TestSlow", "1.500s", "75.0%", "Slowest packages", "1. example"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("ci=%v: expected output to contain %q, but it didn't.\nGot: %s", ciMode, expected, output)
+			}
+		}
+		if strings.Contains(output, "TestFast") || strings.Contains(output, "Most time across subtests") {
+			t.Errorf("ci=%v: output should only contain the top entry.\nGot: %s", ciMode, output)
+		}
+	}
+}
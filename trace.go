@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceSpan is the lifetime of a package or test, from its first event to its completion
+type traceSpan struct {
+	pkg      string
+	test     string // Empty for the package itself
+	start    time.Time
+	end      time.Time
+	status   string         // "pass", "fail" or "skip", empty while running
+	gaps     [][2]time.Time // Paused intervals between "pause" and "cont"
+	pausedAt time.Time
+}
+
+// TraceRecorder collects event timestamps and writes them as a Chrome
+// trace-event file that can be opened in Perfetto or chrome://tracing
+type TraceRecorder struct {
+	mu       sync.Mutex
+	first    time.Time
+	last     time.Time
+	packages []string              // Packages in order of appearance
+	spans    map[string]*traceSpan // Keyed by package and test name
+	order    []*traceSpan
+}
+
+// NewTraceRecorder creates an empty TraceRecorder
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{spans: make(map[string]*traceSpan)}
+}
+
+// Record adds an event to the trace. Events without a timestamp are ignored.
+func (t *TraceRecorder) Record(event TestEvent) {
+	if event.Package == "" || event.Time.IsZero() {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if event.Time.After(t.last) {
+		t.last = event.Time
+	}
+
+	// The package span starts with its first event of any kind
+	if t.spans[event.Package+"\x00"] == nil {
+		t.packages = append(t.packages, event.Package)
+		start := event.Time
+		if event.Test == "" {
+			start = startOfCompletion(event)
+		}
+		t.addSpan(event.Package, "", start)
+	}
+
+	span := t.spans[event.Package+"\x00"+event.Test]
+	switch event.Action {
+	case "run":
+		if span == nil {
+			t.addSpan(event.Package, event.Test, event.Time)
+		}
+	case "pause":
+		if span != nil {
+			span.pausedAt = event.Time
+		}
+	case "cont":
+		if span != nil && !span.pausedAt.IsZero() {
+			span.gaps = append(span.gaps, [2]time.Time{span.pausedAt, event.Time})
+			span.pausedAt = time.Time{}
+		}
+	case "pass", "fail", "skip":
+		if span == nil {
+			span = t.addSpan(event.Package, event.Test, startOfCompletion(event))
+		}
+		if !span.pausedAt.IsZero() {
+			span.gaps = append(span.gaps, [2]time.Time{span.pausedAt, event.Time})
+			span.pausedAt = time.Time{}
+		}
+		span.end = event.Time
+		span.status = event.Action
+	}
+}
+
+func (t *TraceRecorder) addSpan(pkg, test string, start time.Time) *traceSpan {
+	if t.first.IsZero() || start.Before(t.first) {
+		t.first = start
+	}
+	span := &traceSpan{pkg: pkg, test: test, start: start}
+	t.spans[pkg+"\x00"+test] = span
+	t.order = append(t.order, span)
+	return span
+}
+
+// startOfCompletion derives the start of a span first seen at its completion
+// from the elapsed time. Other events start the span at their own time.
+func startOfCompletion(event TestEvent) time.Time {
+	switch event.Action {
+	case "pass", "fail", "skip":
+		return event.Time.Add(-time.Duration(event.Elapsed * float64(time.Second)))
+	}
+	return event.Time
+}
+
+// traceEvent is a single entry of the Chrome trace-event format
+type traceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat,omitempty"`
+	Phase     string         `json:"ph"`
+	Timestamp float64        `json:"ts"` // Microseconds since the first event
+	Duration  float64        `json:"dur"`
+	PID       int            `json:"pid"`
+	TID       int            `json:"tid"`
+	ColorName string         `json:"cname,omitempty"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+// traceFile is the top-level object of the Chrome trace-event format
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// events converts the recorded spans into trace events. Every package is a
+// process; its tests are spread over lanes (threads) so that overlapping
+// parallel tests don't overlap on one lane and subtests nest under their parent.
+func (t *TraceRecorder) events() []traceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byPackage := make(map[string][]*traceSpan)
+	for _, span := range t.order {
+		byPackage[span.pkg] = append(byPackage[span.pkg], span)
+	}
+
+	var events []traceEvent
+	for i, pkg := range t.packages {
+		pid := i + 1
+		events = append(events,
+			traceEvent{Name: "process_name", Phase: "M", PID: pid, Args: map[string]any{"name": pkg}},
+			traceEvent{Name: "process_sort_index", Phase: "M", PID: pid, Args: map[string]any{"sort_index": i}},
+		)
+
+		lanes := assignTraceLanes(byPackage[pkg], t.last)
+		for tid := range lanes.count {
+			name := "package"
+			if tid > 0 {
+				name = fmt.Sprintf("lane %d", tid)
+			}
+			events = append(events, traceEvent{Name: "thread_name", Phase: "M", PID: pid, TID: tid, Args: map[string]any{"name": name}})
+		}
+
+		for _, span := range byPackage[pkg] {
+			events = append(events, t.spanEvents(span, pid, lanes.tids[span])...)
+		}
+	}
+	return events
+}
+
+// spanEvents returns one complete event per active segment of a span, so
+// paused intervals show up as gaps
+func (t *TraceRecorder) spanEvents(span *traceSpan, pid, tid int) []traceEvent {
+	end, status := span.end, span.status
+	if status == "" {
+		end, status = t.last, "incomplete"
+	}
+
+	name, category := span.test, "test"
+	if name == "" {
+		name, category = span.pkg, "package"
+	}
+	args := map[string]any{
+		"status":  status,
+		"elapsed": formatDuration(end.Sub(span.start).Seconds()),
+	}
+	if len(span.gaps) > 0 {
+		paused := time.Duration(0)
+		for _, gap := range span.gaps {
+			paused += gap[1].Sub(gap[0])
+		}
+		args["paused"] = formatDuration(paused.Seconds())
+	}
+	colorName := ""
+	switch status {
+	case "fail":
+		colorName = "terrible"
+	case "skip", "incomplete":
+		colorName = "grey"
+	}
+
+	var events []traceEvent
+	segmentStart := span.start
+	for _, gap := range append(span.gaps, [2]time.Time{end, end}) {
+		if gap[0].Before(segmentStart) {
+			continue
+		}
+		events = append(events, traceEvent{
+			Name:      name,
+			Category:  category,
+			Phase:     "X",
+			Timestamp: t.micros(segmentStart),
+			Duration:  float64(gap[0].Sub(segmentStart).Microseconds()),
+			PID:       pid,
+			TID:       tid,
+			ColorName: colorName,
+			Args:      args,
+		})
+		segmentStart = gap[1]
+	}
+	return events
+}
+
+func (t *TraceRecorder) micros(at time.Time) float64 {
+	return float64(at.Sub(t.first).Microseconds())
+}
+
+// traceLanes maps spans of one package to the lane they are drawn on
+type traceLanes struct {
+	tids  map[*traceSpan]int
+	count int
+}
+
+// assignTraceLanes puts every span on the first lane where it either does not
+// overlap anything or fits within its parent, the innermost open span there
+func assignTraceLanes(spans []*traceSpan, last time.Time) traceLanes {
+	endOf := func(span *traceSpan) time.Time {
+		if span.status == "" {
+			return last
+		}
+		return span.end
+	}
+
+	sorted := make([]*traceSpan, len(spans))
+	copy(sorted, spans)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].start.Equal(sorted[j].start) {
+			return sorted[i].start.Before(sorted[j].start)
+		}
+		// Parents before children that start at the same time
+		return len(sorted[i].test) < len(sorted[j].test)
+	})
+
+	lanes := traceLanes{tids: make(map[*traceSpan]int)}
+	var stacks [][]*traceSpan // Open spans on each lane, innermost last
+	for _, span := range sorted {
+		tid := -1
+		for i, stack := range stacks {
+			for len(stack) > 0 && !endOf(stack[len(stack)-1]).After(span.start) {
+				stack = stack[:len(stack)-1]
+			}
+			stacks[i] = stack
+			if len(stack) == 0 {
+				tid = i
+				break
+			}
+			if top := stack[len(stack)-1]; isTraceParent(top, span) && !endOf(span).After(endOf(top)) {
+				tid = i
+				break
+			}
+		}
+		if tid == -1 {
+			tid = len(stacks)
+			stacks = append(stacks, nil)
+		}
+		stacks[tid] = append(stacks[tid], span)
+		lanes.tids[span] = tid
+	}
+	lanes.count = len(stacks)
+	return lanes
+}
+
+// isTraceParent reports whether parent is the package or an ancestor test of child
+func isTraceParent(parent, child *traceSpan) bool {
+	if parent.pkg != child.pkg {
+		return false
+	}
+	return parent.test == "" || strings.HasPrefix(child.test, parent.test+"/")
+}
+
+// WriteTo writes the trace in the Chrome trace-event JSON format
+func (t *TraceRecorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(traceFile{TraceEvents: t.events(), DisplayTimeUnit: "ms"})
+	if err != nil {
+		return 0, fmt.Errorf("encoding trace: %w", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// WriteTraceFile writes the recorded trace to path
+func WriteTraceFile(path string, trace *TraceRecorder) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing trace file: %w", err)
+	}
+	if _, err := trace.WriteTo(file); err != nil {
+		file.Close()
+		return fmt.Errorf("writing trace file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing trace file: %w", err)
+	}
+	return nil
+}
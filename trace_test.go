@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTraceRecorder_WriteTo(t *testing.T) {
+	t.Parallel()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	trace := NewTraceRecorder()
+	for _, event := range []TestEvent{
+		{Time: at(0), Action: "start", Package: "example"},
+		{Time: at(10), Action: "run", Package: "example", Test: "TestParent"},
+		{Time: at(11), Action: "run", Package: "example", Test: "TestParent/a"},
+		{Time: at(12), Action: "pause", Package: "example", Test: "TestParent/a"},
+		{Time: at(13), Action: "run", Package: "example", Test: "TestParent/b"},
+		{Time: at(14), Action: "pause", Package: "example", Test: "TestParent/b"},
+		{Time: at(20), Action: "cont", Package: "example", Test: "TestParent/a"},
+		{Time: at(20), Action: "cont", Package: "example", Test: "TestParent/b"},
+		{Time: at(50), Action: "pass", Package: "example", Test: "TestParent/a", Elapsed: 0.039},
+		{Time: at(60), Action: "fail", Package: "example", Test: "TestParent/b", Elapsed: 0.047},
+		{Time: at(60), Action: "fail", Package: "example", Test: "TestParent", Elapsed: 0.05},
+		{Time: at(70), Action: "run", Package: "example", Test: "TestRunning"},
+		{Time: at(80), Action: "fail", Package: "example", Elapsed: 0.08},
+		{Time: at(5), Action: "pass", Package: "other", Elapsed: 0.005},
+		{Action: "output", Package: "other", Output: "no timestamp\n"},
+	} {
+		trace.Record(event)
+	}
+
+	var buf bytes.Buffer
+	if _, err := trace.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	var file traceFile
+	if err := json.Unmarshal(buf.Bytes(), &file); err != nil {
+		t.Fatalf("trace is not valid JSON: %v", err)
+	}
+
+	processes := make(map[int]string)
+	spans := make(map[string][]traceEvent)
+	for _, event := range file.TraceEvents {
+		switch {
+		case event.Phase == "M" && event.Name == "process_name":
+			processes[event.PID] = event.Args["name"].(string)
+		case event.Phase == "X":
+			spans[event.Name] = append(spans[event.Name], event)
+		}
+	}
+
+	if processes[1] != "example" || processes[2] != "other" {
+		t.Errorf("processes = %v, want one per package in order of appearance", processes)
+	}
+
+	tests := []struct {
+		name     string
+		segments [][2]float64 // Timestamp and duration in microseconds
+		status   string
+	}{
+		{"example", [][2]float64{{0, 80000}}, "fail"},
+		{"TestParent", [][2]float64{{10000, 50000}}, "fail"},
+		{"TestParent/a", [][2]float64{{11000, 1000}, {20000, 30000}}, "pass"},
+		{"TestParent/b", [][2]float64{{13000, 1000}, {20000, 40000}}, "fail"},
+		{"TestRunning", [][2]float64{{70000, 10000}}, "incomplete"},
+		// The package never started: its start is derived from the elapsed time
+		{"other", [][2]float64{{0, 5000}}, "pass"},
+	}
+	for _, tt := range tests {
+		events := spans[tt.name]
+		if len(events) != len(tt.segments) {
+			t.Errorf("%s: got %d segments, want %d", tt.name, len(events), len(tt.segments))
+			continue
+		}
+		for i, segment := range tt.segments {
+			if events[i].Timestamp != segment[0] || events[i].Duration != segment[1] {
+				t.Errorf("%s segment %d = (%v, %v), want (%v, %v)", tt.name, i, events[i].Timestamp, events[i].Duration, segment[0], segment[1])
+			}
+		}
+		if status := events[0].Args["status"]; status != tt.status {
+			t.Errorf("%s status = %v, want %s", tt.name, status, tt.status)
+		}
+	}
+
+	// Overlapping subtests are on different lanes; the first nests under its parent
+	parent, a, b := spans["TestParent"][0], spans["TestParent/a"][0], spans["TestParent/b"][0]
+	if a.TID != parent.TID {
+		t.Errorf("TestParent/a lane = %d, want its parent's lane %d", a.TID, parent.TID)
+	}
+	if b.TID == a.TID {
+		t.Errorf("overlapping subtests should be on different lanes, both on %d", a.TID)
+	}
+	if spans["example"][0].TID != parent.TID {
+		t.Errorf("top-level test should nest under the package span")
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tuiFilter selects the tests listed in the TUI
+type tuiFilter int
+
+const (
+	tuiFilterAll tuiFilter = iota
+	tuiFilterFailed
+	tuiFilterSlow
+	tuiFilterSkipped
+)
+
+var tuiFilterNames = []string{"All", "Failed", "Slow", "Skipped"}
+
+// tuiRow is a line of the tree: a package, or a test when result is set
+type tuiRow struct {
+	pkg    string
+	result *TestResult
+}
+
+// tuiModel is the state of the interactive result browser. It only works on
+// the processor's final state and renders to plain lines, so the terminal
+// handling in tuiterm.go stays small.
+type tuiModel struct {
+	packages map[string]*PackageState
+	results  map[string]*TestResult
+	config   *Config
+
+	filter       tuiFilter
+	collapsed    map[string]bool // Packages whose tests are hidden
+	rows         []tuiRow
+	cursor       int
+	offset       int // First row shown in the list
+	outputScroll int // First line shown in the output pane
+	listHeight   int // Rows in the list at the last render, for paging
+	status       string
+}
+
+func newTUIModel(packages map[string]*PackageState, results map[string]*TestResult, config *Config) *tuiModel {
+	m := &tuiModel{
+		packages:  packages,
+		results:   results,
+		config:    config,
+		filter:    tuiFilterFailed,
+		collapsed: make(map[string]bool),
+	}
+	// Start with the failures, or everything if nothing failed
+	m.buildRows()
+	if len(m.rows) == 0 {
+		m.filter = tuiFilterAll
+		m.buildRows()
+	}
+	return m
+}
+
+// matches reports whether a test is selected by the current filter
+func (m *tuiModel) matches(result *TestResult) bool {
+	switch m.filter {
+	case tuiFilterFailed:
+		return result.Failed || result.Incomplete
+	case tuiFilterSlow:
+		if m.config == nil || isSyntheticResult(result) {
+			return false
+		}
+		threshold, _ := thresholdFor(m.config.Thresholds, m.config.Threshold, result.Package, result.Test)
+		return exceedsThreshold(result.Elapsed, threshold)
+	case tuiFilterSkipped:
+		return result.Skipped
+	default:
+		return true
+	}
+}
+
+// buildRows lays out the tree for the current filter and collapsed packages.
+// Tests are kept if they or any of their subtests match the filter.
+func (m *tuiModel) buildRows() {
+	byPackage := make(map[string][]*TestResult)
+	visible := make(map[string]bool) // Keyed by results key
+	for _, result := range sortedResults(m.results) {
+		if result.Test == "[PACKAGE]" {
+			if pkg, exists := m.packages[result.Package]; exists && !shouldDisplayPackageFailure(pkg) {
+				continue
+			}
+		}
+		byPackage[result.Package] = append(byPackage[result.Package], result)
+		if !m.matches(result) {
+			continue
+		}
+		for name := result.Test; name != ""; name, _ = splitTestName(name) {
+			visible[result.Package+"/"+name] = true
+		}
+	}
+
+	names := make([]string, 0, len(byPackage))
+	for name := range byPackage {
+		names = append(names, name)
+	}
+	for name := range m.packages {
+		if _, exists := byPackage[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	m.rows = m.rows[:0]
+	for _, name := range names {
+		var tests []tuiRow
+		for _, result := range byPackage[name] {
+			if visible[name+"/"+result.Test] {
+				tests = append(tests, tuiRow{pkg: name, result: result})
+			}
+		}
+		if m.filter != tuiFilterAll && len(tests) == 0 {
+			continue
+		}
+		m.rows = append(m.rows, tuiRow{pkg: name})
+		if !m.collapsed[name] {
+			m.rows = append(m.rows, tests...)
+		}
+	}
+	m.moveCursor(0)
+}
+
+func (m *tuiModel) moveCursor(delta int) {
+	previous := m.cursor
+	m.cursor = max(0, min(m.cursor+delta, len(m.rows)-1))
+	if m.cursor != previous {
+		m.outputScroll = 0
+	}
+}
+
+func (m *tuiModel) selected() (tuiRow, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return tuiRow{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// handleKey applies a key press. It returns whether to quit and text to copy
+// to the clipboard, if any.
+func (m *tuiModel) handleKey(key string) (bool, string) {
+	m.status = ""
+	page := max(1, m.listHeight-1)
+
+	switch key {
+	case "q", "esc", "ctrl-c":
+		return true, ""
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-page)
+	case "pgdown":
+		m.moveCursor(page)
+	case "home", "g":
+		m.moveCursor(-len(m.rows))
+	case "end", "G":
+		m.moveCursor(len(m.rows))
+	case "enter", " ", "left", "right", "h", "l":
+		m.toggle(key)
+	case "tab":
+		m.setFilter((m.filter + 1) % tuiFilter(len(tuiFilterNames)))
+	case "1", "2", "3", "4":
+		m.setFilter(tuiFilter(key[0] - '1'))
+	case "d":
+		m.outputScroll += page
+	case "u":
+		m.outputScroll = max(0, m.outputScroll-page)
+	case "c":
+		if row, ok := m.selected(); ok {
+			command := tuiRerunCommand(row)
+			m.status = "Copied: " + command
+			return false, command
+		}
+	}
+	return false, ""
+}
+
+// toggle collapses or expands the package of the selected row
+func (m *tuiModel) toggle(key string) {
+	row, ok := m.selected()
+	if !ok {
+		return
+	}
+	switch {
+	case row.result != nil && key != "left" && key != "h":
+		return
+	case key == "left" || key == "h":
+		m.collapsed[row.pkg] = true
+	case key == "right" || key == "l":
+		m.collapsed[row.pkg] = false
+	default:
+		m.collapsed[row.pkg] = !m.collapsed[row.pkg]
+	}
+
+	m.buildRows()
+	for i, candidate := range m.rows {
+		if candidate.pkg == row.pkg && candidate.result == nil {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+func (m *tuiModel) setFilter(filter tuiFilter) {
+	if filter < 0 || int(filter) >= len(tuiFilterNames) {
+		return
+	}
+	m.filter = filter
+	m.cursor, m.offset, m.outputScroll = 0, 0, 0
+	m.buildRows()
+}
+
+// tuiRerunCommand returns the go test command running the test or package of a row
+func tuiRerunCommand(row tuiRow) string {
+	if row.result == nil || isSyntheticResult(row.result) {
+		return "go test " + row.pkg
+	}
+	if row.result.FuzzInput != "" {
+		return fuzzRerunCommand(row.result)
+	}
+	parent, leaf := splitTestName(row.result.Test)
+	return fmt.Sprintf("go test -run %s %s", shellQuote(buildRunPattern(parent, []string{leaf})), row.pkg)
+}
+
+// render draws the screen as exactly height lines: a header, the tree, the
+// title of the selected row, its output and a status bar
+func (m *tuiModel) render(width, height int) []string {
+	width, height = max(width, 20), max(height, 8)
+	lines := make([]string, 0, height)
+
+	stats := collectSummaryStats(m.packages, m.results)
+	var filters []string
+	for i, name := range tuiFilterNames {
+		if tuiFilter(i) == m.filter {
+			filters = append(filters, "\033[7m "+name+" \033[0m")
+		} else {
+			filters = append(filters, " "+name+" ")
+		}
+	}
+	header := fmt.Sprintf(" gotestshow  %d tests  ✓ %d  ✗ %d  ⚡ %d  ", stats.totalTests, stats.totalPassed, stats.totalFailed, stats.totalSkipped)
+	lines = append(lines, fitLine(header, width-tuiVisibleWidth(filters))+strings.Join(filters, ""))
+
+	m.listHeight = max(3, (height-3)/2)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.listHeight {
+		m.offset = m.cursor - m.listHeight + 1
+	}
+	for i := m.offset; i < m.offset+m.listHeight; i++ {
+		if i >= len(m.rows) {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, m.renderRow(m.rows[i], width, i == m.cursor))
+	}
+
+	title, output := m.details()
+	lines = append(lines, "\033[7m"+fitLine(" "+title, width)+"\033[0m")
+
+	outputHeight := height - len(lines) - 1
+	m.outputScroll = max(0, min(m.outputScroll, len(output)-outputHeight))
+	for i := m.outputScroll; i < m.outputScroll+outputHeight; i++ {
+		if i < len(output) {
+			lines = append(lines, fitLine(output[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	status := m.status
+	if status == "" {
+		status = "↑↓ move  ←→ fold  1-4/tab filter  u/d scroll output  c copy rerun command  q quit"
+	}
+	lines = append(lines, colorGray+fitLine(" "+status, width)+colorReset)
+	return lines
+}
+
+func (m *tuiModel) renderRow(row tuiRow, width int, selected bool) string {
+	var text, color string
+	if row.result == nil {
+		marker := "▾"
+		if m.collapsed[row.pkg] {
+			marker = "▸"
+		}
+		status, elapsed := "fail", ""
+		if pkg, exists := m.packages[row.pkg]; exists {
+			status = packageStatus(pkg, m.results)
+			elapsed = " (" + formatDuration(pkg.Elapsed) + ")"
+			if pkg.Failed > 0 {
+				elapsed = fmt.Sprintf(" (%d failed, %s)", pkg.Failed, formatDuration(pkg.Elapsed))
+			}
+		}
+		icon, iconColor := tuiStatusIcon(status)
+		text, color = fmt.Sprintf("%s %s %s%s", marker, icon, row.pkg, elapsed), iconColor
+	} else {
+		result := row.result
+		depth := 1
+		name := result.Test
+		if !isSyntheticResult(result) {
+			depth = strings.Count(result.Test, "/") + 1
+			_, name = splitTestName(result.Test)
+		}
+		icon, iconColor := tuiStatusIcon(resultStatus(result))
+		slow := ""
+		if m.config != nil && !isSyntheticResult(result) {
+			if threshold, _ := thresholdFor(m.config.Thresholds, m.config.Threshold, result.Package, result.Test); exceedsThreshold(result.Elapsed, threshold) {
+				slow = " [SLOW]"
+			}
+		}
+		text = fmt.Sprintf("%s%s %s (%s)%s", strings.Repeat("  ", depth), icon, name, formatDuration(result.Elapsed), slow)
+		color = iconColor
+	}
+
+	if selected {
+		return "\033[7m" + fitLine(text, width) + colorReset
+	}
+	return color + fitLine(text, width) + colorReset
+}
+
+// details returns the title and output lines of the selected row
+func (m *tuiModel) details() (string, []string) {
+	row, ok := m.selected()
+	if !ok {
+		return "No tests match the filter", nil
+	}
+
+	if row.result == nil {
+		title := row.pkg
+		var output []string
+		if pkg, exists := m.packages[row.pkg]; exists {
+			title = fmt.Sprintf("%s  %d tests, %d passed, %d failed, %d skipped  %s",
+				row.pkg, pkg.Total, pkg.Passed, pkg.Failed, pkg.Skipped, formatDuration(pkg.Elapsed))
+			output = tuiOutputLines(pkg.Output)
+		}
+		return title, output
+	}
+
+	result := row.result
+	title := result.Test
+	if result.Location != "" {
+		title += "  " + result.Location
+	}
+	title += fmt.Sprintf("  %s  %s", strings.ToUpper(resultStatus(result)), formatDuration(result.Elapsed))
+	output := tuiOutputLines(result.Output)
+	if len(output) == 0 {
+		output = []string{"(no output)"}
+	}
+	return title, output
+}
+
+// tuiOutputLines splits test output into display lines with tabs expanded
+func tuiOutputLines(output []string) []string {
+	var lines []string
+	for _, line := range extractRelevantOutput(output) {
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r\n"), "\t", "    ")
+		lines = append(lines, strings.Split(line, "\n")...)
+	}
+	return lines
+}
+
+func tuiStatusIcon(status string) (string, string) {
+	switch status {
+	case "fail":
+		return "✗", colorRed
+	case "skip":
+		return "⚡", colorYellow
+	case "pass":
+		return "✓", colorGreen
+	default:
+		return "…", colorGray
+	}
+}
+
+// fitLine cuts or pads text without escape sequences to width columns
+func fitLine(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:max(width, 0)])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+// tuiVisibleWidth returns the number of columns of parts, ignoring escape sequences
+func tuiVisibleWidth(parts []string) int {
+	width := 0
+	for _, part := range parts {
+		inEscape := false
+		for _, r := range part {
+			switch {
+			case r == '\033':
+				inEscape = true
+			case inEscape:
+				inEscape = r < '@' || r > '~' || r == '['
+			default:
+				width++
+			}
+		}
+	}
+	return width
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tuiFixture() (map[string]*PackageState, map[string]*TestResult) {
+	packages := map[string]*PackageState{
+		"example/a": {Name: "example/a", Total: 5, Passed: 2, Failed: 1, Skipped: 1, Elapsed: 2},
+		"example/b": {Name: "example/b", Total: 1, Passed: 1, Elapsed: 0.1},
+	}
+	results := map[string]*TestResult{
+		"example/a/TestFast":         {Package: "example/a", Test: "TestFast", Passed: true, Elapsed: 0.01},
+		"example/a/TestSlow":         {Package: "example/a", Test: "TestSlow", Passed: true, Elapsed: 1.5},
+		"example/a/TestTable":        {Package: "example/a", Test: "TestTable", Failed: true, HasSubtest: true, Elapsed: 0.2},
+		"example/a/TestTable/broken": {Package: "example/a", Test: "TestTable/broken", Failed: true, Elapsed: 0.2, Location: "a_test.go:12", Output: []string{"=== RUN   TestTable/broken\n", "    a_test.go:12:\tgot 1\n"}},
+		"example/a/TestSkipped":      {Package: "example/a", Test: "TestSkipped", Skipped: true},
+		"example/b/TestOK":           {Package: "example/b", Test: "TestOK", Passed: true, Elapsed: 0.1},
+	}
+	return packages, results
+}
+
+// tuiRowNames lists the rows of the model as package or test names
+func tuiRowNames(m *tuiModel) []string {
+	var names []string
+	for _, row := range m.rows {
+		if row.result == nil {
+			names = append(names, row.pkg)
+		} else {
+			names = append(names, row.result.Test)
+		}
+	}
+	return names
+}
+
+func TestTUIModel_Filters(t *testing.T) {
+	t.Parallel()
+	packages, results := tuiFixture()
+	m := newTUIModel(packages, results, &Config{Threshold: time.Second})
+
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		// Starts with the failures; parents of matching subtests are kept
+		{"", []string{"example/a", "TestTable", "TestTable/broken"}},
+		{"3", []string{"example/a", "TestSlow"}},
+		{"4", []string{"example/a", "TestSkipped"}},
+		{"1", []string{"example/a", "TestFast", "TestSkipped", "TestSlow", "TestTable", "TestTable/broken", "example/b", "TestOK"}},
+		{"tab", []string{"example/a", "TestTable", "TestTable/broken"}},
+	}
+	for _, tt := range tests {
+		if tt.key != "" {
+			m.handleKey(tt.key)
+		}
+		if names := tuiRowNames(m); !reflect.DeepEqual(names, tt.expected) {
+			t.Errorf("after %q: expected rows %v, got %v", tt.key, tt.expected, names)
+		}
+	}
+}
+
+func TestTUIModel_NavigationAndFolding(t *testing.T) {
+	t.Parallel()
+	packages, results := tuiFixture()
+	m := newTUIModel(packages, results, &Config{Threshold: time.Second})
+	m.handleKey("1")
+
+	m.handleKey("end")
+	if row, _ := m.selected(); row.result == nil || row.result.Test != "TestOK" {
+		t.Fatalf("expected the last row to be selected, got %+v", row)
+	}
+
+	// Folding from a test collapses its package and selects the package
+	m.handleKey("left")
+	if names := tuiRowNames(m); names[len(names)-1] != "example/b" || m.cursor != len(names)-1 {
+		t.Errorf("expected example/b to be collapsed and selected, got rows %v and cursor %d", names, m.cursor)
+	}
+	m.handleKey("enter")
+	if names := tuiRowNames(m); names[len(names)-1] != "TestOK" {
+		t.Errorf("expected example/b to be expanded again, got %v", names)
+	}
+
+	m.handleKey("home")
+	m.handleKey("up")
+	if m.cursor != 0 {
+		t.Errorf("cursor should stay on the first row, got %d", m.cursor)
+	}
+	if quit, _ := m.handleKey("q"); !quit {
+		t.Error("q should quit")
+	}
+}
+
+func TestTUIModel_CopyRerunCommand(t *testing.T) {
+	t.Parallel()
+	packages, results := tuiFixture()
+	m := newTUIModel(packages, results, &Config{Threshold: time.Second})
+
+	m.handleKey("end")
+	_, clipboard := m.handleKey("c")
+	if expected := "go test -run '^TestTable$/^broken$' example/a"; clipboard != expected {
+		t.Errorf("expected %q, got %q", expected, clipboard)
+	}
+	if !strings.Contains(m.status, "Copied") {
+		t.Errorf("expected a status message, got %q", m.status)
+	}
+
+	m.handleKey("home")
+	if _, clipboard := m.handleKey("c"); clipboard != "go test example/a" {
+		t.Errorf("expected the package command, got %q", clipboard)
+	}
+}
+
+func TestTUIModel_Render(t *testing.T) {
+	t.Parallel()
+	packages, results := tuiFixture()
+	m := newTUIModel(packages, results, &Config{Threshold: time.Second})
+	m.handleKey("end")
+
+	lines := m.render(60, 12)
+	if len(lines) != 12 {
+		t.Fatalf("expected 12 lines, got %d", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, expected := range []string{"6 tests", "Failed", "broken (200ms)", "TestTable/broken  a_test.go:12  FAIL", "    a_test.go:12:    got 1", "↑↓ move"} {
+		if !strings.Contains(screen, expected) {
+			t.Errorf("expected the screen to contain %q.\nGot:\n%s", expected, screen)
+		}
+	}
+	if strings.Contains(screen, "=== RUN") {
+		t.Error("run markers should not be shown")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"q", []string{"q"}},
+		{"\033[A\033[B", []string{"up", "down"}},
+		{"\033", []string{"esc"}},
+		{"\033[6~j\r", []string{"pgdown", "j", "enter"}},
+		{"\t\x03", []string{"tab", "ctrl-c"}},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parseKeys(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// runTUI shows the interactive result browser on the controlling terminal.
+// Standard input carries the go test stream, so keys are read from /dev/tty,
+// which is switched to raw mode with stty for the duration.
+func runTUI(model *tuiModel) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("opening terminal for -tui: %w", err)
+	}
+	defer tty.Close()
+
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		return fmt.Errorf("reading terminal settings: %w", err)
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return fmt.Errorf("switching terminal to raw mode: %w", err)
+	}
+	defer stty(tty, strings.TrimSpace(saved))
+
+	// Alternate screen and hidden cursor, restored on exit
+	fmt.Fprint(tty, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(tty, "\033[?25h\033[?1049l")
+
+	buf := make([]byte, 64)
+	for {
+		width, height := terminalSize(tty)
+		drawTUI(tty, model.render(width, height))
+
+		n, err := tty.Read(buf)
+		if err != nil {
+			return fmt.Errorf("reading terminal: %w", err)
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			quit, clipboard := model.handleKey(key)
+			if quit {
+				return nil
+			}
+			if clipboard != "" {
+				copyToClipboard(tty, clipboard)
+			}
+		}
+	}
+}
+
+// stty runs stty on the terminal and returns its output
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// terminalSize returns the columns and rows of the terminal, or 80x24 if unknown
+func terminalSize(tty *os.File) (int, int) {
+	output, err := stty(tty, "size")
+	if err == nil {
+		if fields := strings.Fields(output); len(fields) == 2 {
+			rows, rowsErr := strconv.Atoi(fields[0])
+			cols, colsErr := strconv.Atoi(fields[1])
+			if rowsErr == nil && colsErr == nil && rows > 0 && cols > 0 {
+				return cols, rows
+			}
+		}
+	}
+	return 80, 24
+}
+
+// drawTUI redraws the whole screen in one write to avoid flicker
+func drawTUI(w io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for i, line := range lines {
+		if i > 0 {
+			// Raw mode does not translate \n into \r\n
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\033[K")
+	}
+	io.WriteString(w, b.String())
+}
+
+// copyToClipboard asks the terminal to set the clipboard with an OSC 52
+// sequence, which also works over SSH
+func copyToClipboard(w io.Writer, text string) {
+	fmt.Fprintf(w, "\033]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+// parseKeys converts bytes read from a raw terminal into key names. Escape
+// sequences of one key press arrive in a single read.
+func parseKeys(data []byte) []string {
+	sequences := map[string]string{
+		"\033[A": "up", "\033[B": "down", "\033[C": "right", "\033[D": "left",
+		"\033OA": "up", "\033OB": "down", "\033OC": "right", "\033OD": "left",
+		"\033[5~": "pgup", "\033[6~": "pgdown",
+		"\033[H": "home", "\033[F": "end", "\033[1~": "home", "\033[4~": "end",
+	}
+
+	var keys []string
+	for len(data) > 0 {
+		if data[0] == '\033' {
+			if len(data) == 1 {
+				return append(keys, "esc")
+			}
+			matched := false
+			for sequence, key := range sequences {
+				if strings.HasPrefix(string(data), sequence) {
+					keys = append(keys, key)
+					data = data[len(sequence):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// Unknown sequence: ignore the rest of the read
+				return keys
+			}
+			continue
+		}
+
+		switch data[0] {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 3:
+			keys = append(keys, "ctrl-c")
+		case 4:
+			keys = append(keys, "d")
+		case 21:
+			keys = append(keys, "u")
+		default:
+			keys = append(keys, string(data[0]))
+		}
+		data = data[1:]
+	}
+	return keys
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// watchGraph maps the packages under watch to what their tests depend on
+type watchGraph struct {
+	targets []string                   // Packages matched by the patterns, which are tested
+	deps    map[string]map[string]bool // Target -> packages its test binary is built from, itself included
+	dirs    map[string]string          // Directory -> package of the main module in it
+	roots   []string                   // Directories of the main modules, searched for new packages
+}
+
+// loadWatchGraph lists the packages matching patterns and their dependencies with go list
+func loadWatchGraph(patterns []string) (*watchGraph, error) {
+	targets, err := goList(append([]string{"-e", "-f", "{{.ImportPath}}"}, patterns...))
+	if err != nil {
+		return nil, err
+	}
+	listing, err := goList(append([]string{"-e", "-deps", "-test", "-f",
+		"{{.ImportPath}}\t{{.Dir}}\t{{if .Module}}{{.Module.Main}}{{end}}\t{{join .Deps \" \"}}"}, patterns...))
+	if err != nil {
+		return nil, err
+	}
+	modules, err := moduleDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	g := parseWatchGraph(strings.Fields(targets), listing)
+	for _, dir := range modules {
+		g.roots = append(g.roots, dir)
+	}
+	sort.Strings(g.roots)
+	return g, nil
+}
+
+func goList(args []string) (string, error) {
+	cmd := exec.Command("go", append([]string{"list"}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go list: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// parseWatchGraph builds the graph from the output of go list -deps -test.
+// The test binary of package P is listed as "P.test" and its dependencies
+// include test variants such as "P [P.test]", which count as P.
+func parseWatchGraph(targets []string, listing string) *watchGraph {
+	g := &watchGraph{
+		targets: targets,
+		deps:    make(map[string]map[string]bool),
+		dirs:    make(map[string]string),
+	}
+
+	depsOf := make(map[string][]string)
+	for _, line := range strings.Split(listing, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		path, dir, main, deps := fields[0], fields[1], fields[2], fields[3]
+		depsOf[path] = strings.Fields(deps)
+		// Test variants and test binaries share the directory of their package
+		if main == "true" && dir != "" && !strings.Contains(path, " [") && !strings.HasSuffix(path, ".test") {
+			g.dirs[dir] = path
+		}
+	}
+
+	for _, target := range targets {
+		set := map[string]bool{target: true}
+		for _, name := range []string{target, target + ".test"} {
+			for _, dep := range depsOf[name] {
+				dep, _, _ = strings.Cut(dep, " [")
+				set[dep] = true
+			}
+		}
+		g.deps[target] = set
+	}
+	return g
+}
+
+// affected returns the targets whose tests depend on a package in one of the
+// changed directories. A change outside the known packages affects all targets.
+func (g *watchGraph) affected(changedDirs []string) []string {
+	changed := make(map[string]bool)
+	for _, dir := range changedDirs {
+		pkg, exists := g.dirs[dir]
+		if !exists {
+			return g.targets
+		}
+		changed[pkg] = true
+	}
+
+	var affected []string
+	for _, target := range g.targets {
+		if len(g.dependsOn(target, changed)) > 0 {
+			affected = append(affected, target)
+		}
+	}
+	return affected
+}
+
+// dependsOn returns the packages among pkgs that the tests of target are built from
+func (g *watchGraph) dependsOn(target string, pkgs map[string]bool) []string {
+	var matched []string
+	for pkg := range pkgs {
+		if g.deps[target][pkg] {
+			matched = append(matched, pkg)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// watchedDirs returns the directories of the main module's packages
+func (g *watchGraph) watchedDirs() []string {
+	dirs := make([]string, 0, len(g.dirs))
+	for dir := range g.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// fileStamp identifies a version of a file for polling
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotGoFiles records the .go files in dirs
+func snapshotGoFiles(dirs []string) map[string]fileStamp {
+	snapshot := make(map[string]fileStamp)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			snapshot[filepath.Join(dir, entry.Name())] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return snapshot
+}
+
+// goFileDirs returns the directories under the module roots that hold .go
+// files, skipping the directories the go command ignores and nested modules
+func goFileDirs(roots []string) map[string]bool {
+	isRoot := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		isRoot[root] = true
+	}
+
+	dirs := make(map[string]bool)
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !entry.IsDir() {
+				if strings.HasSuffix(entry.Name(), ".go") {
+					dirs[filepath.Dir(path)] = true
+				}
+				return nil
+			}
+			if path == root {
+				return nil
+			}
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" || isRoot[path] {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+	return dirs
+}
+
+// changedFiles returns the files added, modified or removed between two snapshots
+func changedFiles(before, after map[string]fileStamp) []string {
+	var changed []string
+	for path, stamp := range after {
+		if previous, exists := before[path]; !exists || previous != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, exists := after[path]; !exists {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// watchProcessor presents the events of a rerun merged with the last results
+// of the packages that were not rerun, so the summary covers all packages
+type watchProcessor struct {
+	EventProcessor
+	rerun            map[string]bool
+	previousPackages map[string]*PackageState
+	previousResults  map[string]*TestResult
+}
+
+func (p *watchProcessor) GetPackages() map[string]*PackageState {
+	packages := make(map[string]*PackageState)
+	for name, pkg := range p.previousPackages {
+		if !p.rerun[name] {
+			packages[name] = pkg
+		}
+	}
+	for name, pkg := range p.EventProcessor.GetPackages() {
+		packages[name] = pkg
+	}
+	return packages
+}
+
+func (p *watchProcessor) GetResults() map[string]*TestResult {
+	results := make(map[string]*TestResult)
+	for key, result := range p.previousResults {
+		if !p.rerun[result.Package] {
+			results[key] = result
+		}
+	}
+	for key, result := range p.EventProcessor.GetResults() {
+		results[key] = result
+	}
+	return results
+}
+
+// runWatchCommand implements `gotestshow watch`: it tests the packages, then
+// polls the module's .go files and reruns the packages affected by changes
+func runWatchCommand(args []string, stdout, stderr io.Writer) int {
+	config, err := parseConfig(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			NewTerminalDisplay(stdout, true).ShowHelp()
+			return 0
+		}
+		fmt.Fprintf(stderr, "%s\n", err)
+		return 1
+	}
+	if config.TUI {
+		fmt.Fprintln(stderr, "-tui cannot be used with watch")
+		return 1
+	}
+	patterns := config.Packages
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	graph, err := loadWatchGraph(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var packages map[string]*PackageState
+	var results map[string]*TestResult
+	rerun, reason := graph.targets, "initial run"
+	for {
+		if len(rerun) > 0 {
+			packages, results = runWatchCycle(config, rerun, reason, packages, results, stdout, stderr)
+		}
+
+		snapshot := snapshotGoFiles(graph.watchedDirs())
+		changed, err := waitForChanges(ctx, snapshot, graph.watchedDirs(), graph.roots, config.WatchInterval)
+		if err != nil {
+			return 0
+		}
+
+		// Imports may have changed along with the files
+		if graph, err = loadWatchGraph(patterns); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		dirs := make([]string, 0, len(changed))
+		for _, path := range changed {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		rerun = graph.affected(dirs)
+		reason = describeChanges(changed)
+	}
+}
+
+// waitForChanges polls until a .go file changes and returns the changed files.
+// Changes are collected until one interval passes without further changes, so
+// that saving several files reruns the tests once. The files of a directory
+// that appears under the module roots count as changed, so the packages are
+// listed again and a new package is tested without restarting watch mode.
+func waitForChanges(ctx context.Context, snapshot map[string]fileStamp, dirs, roots []string, interval time.Duration) ([]string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	known := goFileDirs(roots)
+	dirs = append([]string(nil), dirs...)
+	for _, dir := range dirs {
+		known[dir] = true
+	}
+
+	var changed []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		for dir := range goFileDirs(roots) {
+			if !known[dir] {
+				known[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+		current := snapshotGoFiles(dirs)
+		files := changedFiles(snapshot, current)
+		if len(files) == 0 && len(changed) > 0 {
+			sort.Strings(changed)
+			return changed, nil
+		}
+		changed = append(changed, files...)
+		snapshot = current
+	}
+}
+
+func describeChanges(changed []string) string {
+	names := make([]string, 0, len(changed))
+	for _, path := range changed {
+		names = append(names, filepath.Base(path))
+	}
+	if len(names) > 3 {
+		names = append(names[:3], fmt.Sprintf("%d more", len(names)-3))
+	}
+	return "changed " + strings.Join(names, ", ")
+}
+
+// runWatchCycle runs go test -json for the given packages through the usual
+// display and returns the combined state of all packages
+func runWatchCycle(config *Config, rerun []string, reason string, packages map[string]*PackageState, results map[string]*TestResult, stdout, stderr io.Writer) (map[string]*PackageState, map[string]*TestResult) {
+	header := fmt.Sprintf("Running %d packages (%s)", len(rerun), reason)
+	if len(rerun) == 1 {
+		header = fmt.Sprintf("Running %s (%s)", rerun[0], reason)
+	}
+	if config.CIMode {
+		fmt.Fprintf(stdout, "\n%s\n", header)
+	} else {
+		// Clear the screen for every run
+		fmt.Fprint(stdout, "\033[H\033[2J")
+		fmt.Fprintf(stdout, "%s👀 %s%s\n\n", colorBlue, header, colorReset)
+	}
+
+	rerunSet := make(map[string]bool, len(rerun))
+	for _, pkg := range rerun {
+		rerunSet[pkg] = true
+	}
+	processor := &watchProcessor{
+		EventProcessor:   NewEventProcessor(),
+		rerun:            rerunSet,
+		previousPackages: packages,
+		previousResults:  results,
+	}
+	runGoTest(rerun, processor, config, packages != nil, stdout, stderr)
+
+	if config.CIMode {
+		fmt.Fprintln(stdout, "\nWatching for changes...")
+	} else {
+		fmt.Fprintf(stdout, "\n%sWatching for changes... (Ctrl-C to quit)%s\n", colorGray, colorReset)
+	}
+	return processor.GetPackages(), processor.GetResults()
+}
+
+// runGoTest runs go test -json for the packages and shows its events through
+// the usual display, returning the exit code of the run. With -coverprofile,
+// the profile of the run is merged into the file; keepCoverage keeps the
+// blocks of other packages that are already in it.
+func runGoTest(packages []string, processor EventProcessor, config *Config, keepCoverage bool, stdout, stderr io.Writer) int {
+	args := []string{"test", "-json"}
+	var profilePath string
+	if config.CoverProfile != "" {
+		profile, err := os.CreateTemp("", "gotestshow-*.cover")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		profile.Close()
+		defer os.Remove(profile.Name())
+		profilePath = profile.Name()
+		args = append(args, "-coverprofile="+profilePath)
+	} else if config.Policy != nil && len(config.Policy.MinCoverage) > 0 {
+		args = append(args, "-cover")
+	}
+
+	cmd := exec.Command("go", append(args, packages...)...)
+	cmd.Stderr = stderr
+	input, err := cmd.StdoutPipe()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(stderr, "Error: starting go test: %v\n", err)
+		return 1
+	}
+
+	display := NewTerminalDisplay(stdout, true)
+	display.SetConfig(config)
+	runner := NewRunner(processor, display, input, stdout)
+	runner.SetConfig(config)
+	exitCode := runner.Run()
+
+	// go test exits with an error if tests failed, which the display already reported
+	_ = cmd.Wait()
+
+	if profilePath != "" {
+		profile, err := loadCoverProfile(profilePath)
+		if err == nil {
+			profile, err = updateCoverProfile(config.CoverProfile, profile, packages, keepCoverage)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		total, covered := profile.statements()
+		coverage := 0.0
+		if total > 0 {
+			coverage = float64(covered) * 100 / float64(total)
+		}
+		fmt.Fprintf(stdout, "\nCoverage profile written to %s (%s of statements)\n", config.CoverProfile, formatCoverage(coverage))
+	}
+	return exitCode
+}
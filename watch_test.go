@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// watchListing is go list -deps -test output for a module where example/api
+// imports example/db, and the tests of example/web import example/api
+const watchListing = "fmt\t/go/src/fmt\t\t\n" +
+	"example/db\t/src/db\ttrue\tfmt\n" +
+	"example/db.test\t/src/db\ttrue\texample/db fmt testing\n" +
+	"example/api\t/src/api\ttrue\texample/db fmt\n" +
+	"example/api [example/api.test]\t/src/api\ttrue\texample/db fmt\n" +
+	"example/api.test\t/src/api\ttrue\texample/api [example/api.test] example/db fmt testing\n" +
+	"example/web\t/src/web\ttrue\tfmt\n" +
+	"example/web_test [example/web.test]\t/src/web\ttrue\texample/api example/db example/web fmt testing\n" +
+	"example/web.test\t/src/web\ttrue\texample/api example/db example/web example/web_test [example/web.test] fmt testing\n"
+
+func TestWatchGraph_Affected(t *testing.T) {
+	t.Parallel()
+	graph := parseWatchGraph([]string{"example/db", "example/api", "example/web"}, watchListing)
+
+	expectedDirs := map[string]string{"/src/db": "example/db", "/src/api": "example/api", "/src/web": "example/web"}
+	if !reflect.DeepEqual(graph.dirs, expectedDirs) {
+		t.Errorf("expected dirs %v, got %v", expectedDirs, graph.dirs)
+	}
+
+	tests := []struct {
+		name     string
+		changed  []string
+		expected []string
+	}{
+		{"dependency of everything", []string{"/src/db"}, []string{"example/db", "example/api", "example/web"}},
+		{"imported only by tests", []string{"/src/api"}, []string{"example/api", "example/web"}},
+		{"leaf package", []string{"/src/web"}, []string{"example/web"}},
+		{"unknown directory", []string{"/src/new"}, []string{"example/db", "example/api", "example/web"}},
+	}
+	for _, tt := range tests {
+		if got := graph.affected(tt.changed); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	before := map[string]fileStamp{
+		"/src/a.go": {modTime: now, size: 10},
+		"/src/b.go": {modTime: now, size: 10},
+		"/src/c.go": {modTime: now, size: 10},
+	}
+	after := map[string]fileStamp{
+		"/src/a.go": {modTime: now, size: 10},
+		"/src/b.go": {modTime: now.Add(time.Second), size: 10},
+		"/src/d.go": {modTime: now, size: 5},
+	}
+
+	expected := []string{"/src/b.go", "/src/c.go", "/src/d.go"}
+	if got := changedFiles(before, after); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestGoFileDirs(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, name := range []string{
+		"main.go", "api/api.go", "api/testdata/fixture.go", ".cache/x.go",
+		"_old/old.go", "vendor/lib/lib.go", "tools/go.mod", "tools/tool.go", "docs/README.md",
+	} {
+		writeFile(t, filepath.Join(root, name), "package x\n")
+	}
+
+	expected := map[string]bool{root: true, filepath.Join(root, "api"): true}
+	if got := goFileDirs([]string{root}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestWaitForChanges_NewPackage(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "api", "api.go"), "package api\n")
+	writeFile(t, filepath.Join(root, "scratch", "notes.go"), "package scratch\n")
+	dirs := []string{filepath.Join(root, "api")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := os.Mkdir(filepath.Join(root, "db"), 0o755); err != nil {
+			t.Error(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "db", "db.go"), []byte("package db\n"), 0o644); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	// Directories with .go files that exist when watching starts are not changes
+	changed, err := waitForChanges(ctx, snapshotGoFiles(dirs), dirs, []string{root}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitForChanges() error = %v", err)
+	}
+	if expected := []string{filepath.Join(root, "db", "db.go")}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+}
+
+func TestWatchProcessor_MergesPreviousResults(t *testing.T) {
+	t.Parallel()
+	processor := &watchProcessor{
+		EventProcessor: NewEventProcessor(),
+		rerun:          map[string]bool{"example/api": true},
+		previousPackages: map[string]*PackageState{
+			"example/api": {Name: "example/api", Total: 1, Failed: 1},
+			"example/db":  {Name: "example/db", Total: 1, Passed: 1},
+		},
+		previousResults: map[string]*TestResult{
+			"example/api/TestOld": {Package: "example/api", Test: "TestOld", Failed: true},
+			"example/db/TestOK":   {Package: "example/db", Test: "TestOK", Passed: true},
+		},
+	}
+
+	for _, event := range []TestEvent{
+		{Action: "run", Package: "example/api", Test: "TestNew"},
+		{Action: "pass", Package: "example/api", Test: "TestNew"},
+		{Action: "pass", Package: "example/api"},
+	} {
+		processor.ProcessEvent(event)
+	}
+
+	packages := processor.GetPackages()
+	if len(packages) != 2 || packages["example/api"].Passed != 1 || packages["example/api"].Failed != 0 {
+		t.Errorf("expected the rerun state of example/api next to example/db, got %+v", packages)
+	}
+	results := processor.GetResults()
+	if _, exists := results["example/api/TestOld"]; exists {
+		t.Error("results of a rerun package should be replaced")
+	}
+	for _, key := range []string{"example/api/TestNew", "example/db/TestOK"} {
+		if _, exists := results[key]; !exists {
+			t.Errorf("expected %s in the results", key)
+		}
+	}
+}